@@ -35,6 +35,7 @@ const (
 	// API endpoints
 	authTokenURL  = "/auth-token"
 	hashUploadURL = "/upload/hash"
+	rpcURL        = "/rpc/"
 )
 
 // RequestError records an error, URL and code.
@@ -54,6 +55,28 @@ func newRequestError(code int, url string, err error) *RequestError {
 	return &RequestError{Code: code, URL: url, Err: err}
 }
 
+// RPCError is a JSON-RPC error returned by vfs.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return "rpc error " + strconv.Itoa(e.Code) + ": " + e.Message
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
 type Opts struct {
 	ApiURL         string        // Base URL for API endpoints
 	PublicURL      string        // Base URL for Public Files
@@ -213,6 +236,102 @@ func (c *Client) UploadFile(ctx context.Context, token, namespace, filename stri
 	return h, nil
 }
 
+// call calls vfs JSON-RPC method with named params and unmarshals result into res.
+func (c *Client) call(ctx context.Context, token, method string, params, res interface{}) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("%s marshal: %w", method, err)
+	}
+
+	u := c.apiURL(rpcURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s request: %w", method, err)
+	}
+
+	// set headers
+	c.setHeaders(ctx, req)
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add(c.opts.AuthHeader, token)
+
+	// do request
+	resp, err := c.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s do: %w", method, newRequestError(0, u, err))
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// check response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s read: %w", method, newRequestError(resp.StatusCode, u, err))
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s: %w", method, newRequestError(resp.StatusCode, u, ErrUnauthorized))
+	default:
+		return fmt.Errorf("%s: %w body=%s", method, newRequestError(resp.StatusCode, u, ErrInternal), respBody)
+	}
+
+	var r rpcResponse
+	if err = json.Unmarshal(respBody, &r); err != nil {
+		return fmt.Errorf("%s json: %w", method, newRequestError(resp.StatusCode, u, err))
+	}
+
+	if r.Error != nil {
+		if r.Error.Code == http.StatusNotFound {
+			return fmt.Errorf("%s: %w", method, newRequestError(r.Error.Code, u, ErrNotFound))
+		}
+		return fmt.Errorf("%s: %w", method, newRequestError(r.Error.Code, u, r.Error))
+	}
+
+	if err = json.Unmarshal(r.Result, res); err != nil {
+		return fmt.Errorf("%s result json: %w", method, err)
+	}
+
+	return nil
+}
+
+// HashInfo is a hash file info from vfs index.
+type HashInfo struct {
+	Hash      string     `json:"hash"`
+	Extension string     `json:"ext"`
+	Size      int        `json:"size"`
+	Width     int        `json:"width"`
+	Height    int        `json:"height"`
+	Blurhash  *string    `json:"blurhash"`
+	IndexedAt *time.Time `json:"indexedAt"` // nil if file is not indexed yet
+	Error     string     `json:"error,omitempty"`
+}
+
+// GetHashInfo returns hash file info: extension, size, dimensions, blurhash and indexing status.
+// Use empty namespace for default. Returns ErrNotFound if hash was not found.
+func (c *Client) GetHashInfo(ctx context.Context, token, namespace, hash string) (*HashInfo, error) {
+	var r HashInfo
+	params := map[string]interface{}{"namespace": namespace, "hash": hash}
+	if err := c.call(ctx, token, "vfs.GetHashInfo", params, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// GetHashInfoList returns hash files info by hash list, up to 1000 hashes. Not found hashes are skipped.
+// Use empty namespace for default.
+func (c *Client) GetHashInfoList(ctx context.Context, token, namespace string, hashes []string) ([]HashInfo, error) {
+	var r []HashInfo
+	params := map[string]interface{}{"namespace": namespace, "hashes": hashes}
+	if err := c.call(ctx, token, "vfs.GetHashInfoList", params, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
 // HashURL converts a 32-character hash into a hierarchical file path structure.
 // It returns the original hash unchanged if the input is not exactly 32 characters.
 // The resulting path format is: first_char/next_two_chars/full_hash
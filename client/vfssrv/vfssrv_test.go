@@ -2,7 +2,13 @@ package vfssrv
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -99,3 +105,57 @@ func TestClient_FilePath(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetHashInfo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != rpcURL || r.Header.Get(authHeader) != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case req.Method == "vfs.GetHashInfo" && strings.Contains(string(body), `"hash":"64a9f060983200709061894cc5f69f83"`):
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"hash":"64a9f060983200709061894cc5f69f83","ext":"jpg","size":1024,"width":640,"height":480,"blurhash":"LEHV6nWB2yk8pyo0adR*.7kCMdnj","indexedAt":"2026-01-02T15:04:05Z"}}`))
+		case req.Method == "vfs.GetHashInfoList":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":[{"hash":"64a9f060983200709061894cc5f69f83","ext":"jpg","size":1024,"width":0,"height":0,"blurhash":null,"indexedAt":null}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":404,"message":"Not Found"}}`))
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(Opts{ApiURL: ts.URL})
+	ctx := t.Context()
+
+	hi, err := c.GetHashInfo(ctx, "token", "", "64a9f060983200709061894cc5f69f83")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hi.Width != 640 || hi.Height != 480 || hi.Blurhash == nil || hi.IndexedAt == nil || hi.Extension != "jpg" {
+		t.Errorf("GetHashInfo() = %+v", hi)
+	}
+
+	// not found
+	if _, err = c.GetHashInfo(ctx, "token", "", "00000000000000000000000000000000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetHashInfo() err = %v, want %v", err, ErrNotFound)
+	}
+
+	// invalid token
+	if _, err = c.GetHashInfo(ctx, "", "", "64a9f060983200709061894cc5f69f83"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("GetHashInfo() err = %v, want %v", err, ErrUnauthorized)
+	}
+
+	list, err := c.GetHashInfoList(ctx, "token", "", []string{"64a9f060983200709061894cc5f69f83"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].IndexedAt != nil {
+		t.Errorf("GetHashInfoList() = %+v", list)
+	}
+}
@@ -76,7 +76,7 @@ func main() {
 	exitOnError(err)
 
 	sl.Print(ctx, "starting", "app", appName, "version", appkit.Version(), "host", cfg.Server.Host, "port", cfg.Server.Port, "jwtHeader", cfg.Server.JWTHeader)
-	sl.Print(ctx, "app features", "rpc", dbc != nil, "indexer", cfg.Server.Index, "indexBlurhash", cfg.Server.Index && cfg.Server.IndexBlurhash)
+	sl.Print(ctx, "app features", "rpc", dbc != nil, "indexer", cfg.Server.Index, "indexBlurhash", cfg.Server.Index && cfg.Server.IndexBlurhash, "indexVideo", cfg.Server.Index && cfg.Server.IndexFFprobePath != "", "indexPDF", cfg.Server.Index && cfg.Server.IndexPdftoppmPath != "", "watch", cfg.Server.Index && cfg.Server.Watch)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
@@ -112,6 +112,17 @@ func writeConfig(configPath string) error {
 			IndexWorkers:            runtime.NumCPU() / 2,
 			IndexBatchSize:          64,
 			IndexNamespacesPriority: []string{},
+			IndexIdleBackoff:        60,
+			IndexMaxAttempts:        5,
+			IndexRetryDelay:         60,
+			Watch:                   false,
+			WatchDebounce:           2,
+			WatchScanInterval:       3600,
+			StatsInterval:           600,
+			IndexFFprobePath:        "",
+			IndexFFmpegPath:         "",
+			IndexPdftoppmPath:       "",
+			IndexPdfinfoPath:        "",
 		},
 		Database: nil,
 		VFS: vfs.Config{
@@ -126,6 +137,9 @@ func writeConfig(configPath string) error {
 			UploadFormName:   "Filedata",
 			SaltedFilenames:  false,
 			SkipFolderVerify: false,
+			RequireAltText:   false,
+			ConfirmThreshold: 100,
+			TrashRetention:   0,
 		},
 	}
 
@@ -0,0 +1,71 @@
+package vfs
+
+import (
+	"fmt"
+	"image"
+	"sort"
+)
+
+const (
+	paletteSize       = 5
+	maxColorSamples   = 128 // max samples per side
+	colorQuantizeBits = 4   // bits per channel for color buckets
+)
+
+type colorBucket struct {
+	key     uint32
+	count   int
+	r, g, b uint64
+}
+
+// colorPalette returns up to n most used colors in hex format, e.g. "#ff0000". The first color is dominant.
+// Image is sampled by grid with up to maxColorSamples points per side, colors are quantized by colorQuantizeBits.
+func colorPalette(img image.Image, n int) []string {
+	b := img.Bounds()
+	if b.Empty() || n <= 0 {
+		return nil
+	}
+
+	stepX, stepY := max(b.Dx()/maxColorSamples, 1), max(b.Dy()/maxColorSamples, 1)
+	buckets := make(map[uint32]*colorBucket)
+	shift := 16 - colorQuantizeBits
+	for y := b.Min.Y; y < b.Max.Y; y += stepY {
+		for x := b.Min.X; x < b.Max.X; x += stepX {
+			r, g, bl, a := img.At(x, y).RGBA()
+			// skip transparent pixels
+			if a < 0x8000 {
+				continue
+			}
+
+			key := r>>shift<<(2*colorQuantizeBits) | g>>shift<<colorQuantizeBits | bl>>shift
+			cb, ok := buckets[key]
+			if !ok {
+				cb = &colorBucket{key: key}
+				buckets[key] = cb
+			}
+			cb.count++
+			cb.r += uint64(r >> 8)
+			cb.g += uint64(g >> 8)
+			cb.b += uint64(bl >> 8)
+		}
+	}
+
+	list := make([]*colorBucket, 0, len(buckets))
+	for _, cb := range buckets {
+		list = append(list, cb)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].count == list[j].count {
+			return list[i].key < list[j].key
+		}
+		return list[i].count > list[j].count
+	})
+
+	palette := make([]string, 0, n)
+	for i := 0; i < len(list) && i < n; i++ {
+		cb, c := list[i], uint64(list[i].count)
+		palette = append(palette, fmt.Sprintf("#%02x%02x%02x", cb.r/c, cb.g/c, cb.b/c))
+	}
+
+	return palette
+}
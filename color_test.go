@@ -0,0 +1,38 @@
+package vfs
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func Test_colorPalette(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := range 4 {
+		for x := range 4 {
+			switch {
+			case y < 2:
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			case y == 2:
+				img.Set(x, y, color.NRGBA{B: 255, A: 255})
+			case x < 2:
+				img.Set(x, y, color.NRGBA{R: 16, G: 32, B: 48, A: 255})
+			default:
+				img.Set(x, y, color.NRGBA{G: 255, A: 0}) // transparent
+			}
+		}
+	}
+
+	if got, want := colorPalette(img, paletteSize), []string{"#ff0000", "#0000ff", "#102030"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("colorPalette() = %v, want %v", got, want)
+	}
+
+	if got, want := colorPalette(img, 1), []string{"#ff0000"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("colorPalette() = %v, want %v", got, want)
+	}
+
+	if got := colorPalette(image.NewNRGBA(image.Rect(0, 0, 0, 0)), paletteSize); got != nil {
+		t.Errorf("colorPalette() = %v, want nil", got)
+	}
+}
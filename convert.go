@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/vmkteam/vfs/db"
 )
@@ -12,10 +15,13 @@ import (
 const AtomTime = "02.01.2006 15:04"
 
 type Folder struct {
-	ID       int      `json:"id"`
-	Name     string   `json:"name"`
-	ParentID *int     `json:"parentId"`
-	Folders  []Folder `json:"folders,omitempty"`
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	ParentID  *int     `json:"parentId"`
+	Folders   []Folder `json:"folders,omitempty"`
+	Files     *int     `json:"files,omitempty"`     // total files in folder, only for folder tree
+	FilesSize *int64   `json:"filesSize,omitempty"` // total files size in folder, only for folder tree
+	IsDeleted bool     `json:"isDeleted,omitempty"`
 }
 
 type FileParams struct {
@@ -24,20 +30,183 @@ type FileParams struct {
 }
 
 type File struct {
-	ID          int        `json:"id"`
-	Name        string     `json:"name"`
-	Path        string     `json:"path"`
-	PreviewPath string     `json:"previewpath"`
-	RelPath     string     `json:"relpath"`
-	Size        int        `json:"size"`
-	SizeH       []string   `json:"sizeH"`
-	Date        string     `json:"date"`
-	Type        string     `json:"type"`
-	Extension   string     `json:"extension"`
-	Params      FileParams `json:"params"`
-	Shortpath   string     `json:"shortpath"`
-	Width       *int       `json:"width"`
-	Height      *int       `json:"height"`
+	ID          int                 `json:"id"`
+	Name        string              `json:"name"`
+	Path        string              `json:"path"`
+	PreviewPath string              `json:"previewpath"`
+	RelPath     string              `json:"relpath"`
+	Size        int                 `json:"size"`
+	SizeH       []string            `json:"sizeH"`
+	Date        string              `json:"date"`
+	Type        string              `json:"type"`
+	Extension   string              `json:"extension"`
+	Params      FileParams          `json:"params"`
+	Shortpath   string              `json:"shortpath"`
+	Width       *int                `json:"width"`
+	Height      *int                `json:"height"`
+	Tags        []string            `json:"tags,omitempty"`
+	Meta        map[string]string   `json:"meta,omitempty"`  // custom metadata, e.g. source url
+	Texts       map[string]FileText `json:"texts,omitempty"` // localized texts by language code
+	IsPublished bool                `json:"isPublished"`
+}
+
+// TrashFolder is a deleted folder with its sub folders and files.
+type TrashFolder struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	ParentID  *int    `json:"parentId"`
+	DeletedAt string  `json:"deletedAt"`
+	PurgeAt   *string `json:"purgeAt"` // time after which folder is removed, empty if purge is disabled
+}
+
+// TrashFile is a deleted file.
+type TrashFile struct {
+	File      File    `json:"file"`
+	FolderID  int     `json:"folderId"`
+	DeletedAt string  `json:"deletedAt"`
+	PurgeAt   *string `json:"purgeAt"` // time after which file is removed, empty if purge is disabled
+}
+
+type TrashResponse struct {
+	Folders    []TrashFolder `json:"folders"`    // deleted folders with not deleted parent folder
+	Files      []TrashFile   `json:"files"`      // deleted files from not deleted folders
+	TotalFiles int           `json:"totalFiles"` // total deleted files from not deleted folders
+}
+
+// DeleteFolderResponse is a deleted sub folders and files count.
+type DeleteFolderResponse struct {
+	Folders int `json:"folders"` // deleted folders including folder itself
+	Files   int `json:"files"`
+}
+
+// RestoreResponse is a restored folders and files count.
+type RestoreResponse struct {
+	Folders int `json:"folders"`
+	Files   int `json:"files"`
+}
+
+// FolderUsage is a total not deleted files and files size in folder subtree with folder quotas.
+type FolderUsage struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Files      int    `json:"files"`
+	FilesSize  int64  `json:"filesSize"`
+	QuotaFiles *int   `json:"quotaFiles"`
+	QuotaBytes *int64 `json:"quotaBytes"`
+}
+
+// NamespaceUsage is a total hashes and hashes size in namespace.
+type NamespaceUsage struct {
+	Namespace string `json:"namespace"`
+	Hashes    int    `json:"hashes"`
+	FilesSize int64  `json:"filesSize"`
+}
+
+// UsageResponse is a folder usage and hash namespaces usage.
+type UsageResponse struct {
+	Folders    []FolderUsage    `json:"folders"` // folder and its direct sub folders
+	Namespaces []NamespaceUsage `json:"namespaces"`
+}
+
+// CopyFolderResponse is a new folder with new sub folders and files ids.
+type CopyFolderResponse struct {
+	FolderID int   `json:"folderId"` // new folder id
+	Folders  []int `json:"folders"`  // new folder ids including folder
+	Files    []int `json:"files"`
+}
+
+// FileVersion is a prior version of file content.
+type FileVersion struct {
+	ID         int    `json:"id"`
+	Path       string `json:"path"`
+	Size       int    `json:"size"`
+	Type       string `json:"type"`
+	Width      *int   `json:"width"`
+	Height     *int   `json:"height"`
+	ReplacedAt string `json:"replacedAt"`
+}
+
+// FileText is a localized file alt text, caption and copyright.
+type FileText struct {
+	Alt       string `json:"alt"`
+	Caption   string `json:"caption"`
+	Copyright string `json:"copyright"`
+}
+
+type Tag struct {
+	Title string `json:"title"`
+	Files int    `json:"files"` // total tagged files
+}
+
+type HashColors struct {
+	Hash     string   `json:"hash"`
+	Width    int      `json:"width"`
+	Height   int      `json:"height"`
+	Blurhash *string  `json:"blurhash"`
+	Color    *string  `json:"color"`   // dominant color, e.g. "#ff0000"
+	Palette  []string `json:"palette"` // most used colors, dominant is first
+}
+
+type HashInfoResponse struct {
+	Hash      string     `json:"hash"`
+	Extension string     `json:"ext"`
+	Size      int        `json:"size"`
+	Width     int        `json:"width"`
+	Height    int        `json:"height"`
+	Blurhash  *string    `json:"blurhash"`
+	IndexedAt *time.Time `json:"indexedAt"`
+	Error     string     `json:"error,omitempty"` // indexing error
+
+	Attempts      int        `json:"attempts,omitempty"`      // failed indexing attempts
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"` // next indexing attempt for retried hash
+}
+
+// ReindexFilter is a filter for vfs.Reindex, all fields are optional.
+type ReindexFilter struct {
+	Namespace       *string    `json:"namespace"`       // media namespace
+	Extension       *string    `json:"ext"`             // media extension
+	CreatedFrom     *time.Time `json:"createdFrom"`     // created at or after
+	CreatedTo       *time.Time `json:"createdTo"`       // created before
+	ErroredOnly     bool       `json:"erroredOnly"`     // hashes with indexing error only
+	MissingBlurhash bool       `json:"missingBlurhash"` // hashes without blurhash only
+}
+
+// FileSearch is a filter for vfs.SearchFiles, all fields are optional.
+type FileSearch struct {
+	Query        *string    `json:"query"`        // title words or substring
+	RootFolderID *int       `json:"rootFolderId"` // search within folder and its sub folders
+	MimeType     *string    `json:"mimeType"`     // exact mime type or prefix like "image/*"
+	Extension    *string    `json:"extension"`    // file extension without dot
+	MinSize      *int       `json:"minSize"`      // min file size in bytes
+	MaxSize      *int       `json:"maxSize"`      // max file size in bytes
+	CreatedFrom  *time.Time `json:"createdFrom"`  // created at or after
+	CreatedTo    *time.Time `json:"createdTo"`    // created before
+	MinWidth     *int       `json:"minWidth"`
+	MaxWidth     *int       `json:"maxWidth"`
+	MinHeight    *int       `json:"minHeight"`
+	MaxHeight    *int       `json:"maxHeight"`
+	Tags         []string   `json:"tags"` // files with all tags
+}
+
+// FoundFile is a file found by vfs.SearchFiles with its folder branch from root.
+type FoundFile struct {
+	File        File     `json:"file"`
+	FolderID    int      `json:"folderId"`
+	Breadcrumbs []Folder `json:"breadcrumbs"`
+}
+
+type SearchFilesResponse struct {
+	Files []FoundFile `json:"files"`
+	Total int         `json:"total"` // total found files
+}
+
+type SimilarHash struct {
+	Hash      string `json:"hash"`
+	Extension string `json:"ext"`
+	WebPath   string `json:"webPath"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Distance  int    `json:"distance"` // hamming distance between perceptual hashes
 }
 
 func NewFolder(in *db.VfsFolder) *Folder {
@@ -101,7 +270,49 @@ func NewFile(in *db.VfsFile, webpath, previewpath string) *File {
 		Shortpath:   in.Path,
 		Width:       width,
 		Height:      height,
+		Meta:        in.Meta,
+		Texts:       newFileTexts(in.Texts),
+		IsPublished: in.IsPublished,
+	}
+}
+
+func NewFileVersion(in *db.VfsFileVersion, webpath string) *FileVersion {
+	if in == nil {
+		return nil
+	}
+
+	fv := &FileVersion{
+		ID:         in.ID,
+		Path:       path.Join(webpath, in.Path),
+		Type:       in.MimeType,
+		ReplacedAt: in.CreatedAt.Format(AtomTime),
+	}
+	if in.FileSize != nil {
+		fv.Size = *in.FileSize
+	}
+	if in.Params != nil {
+		if in.Params.Width != 0 {
+			fv.Width = &in.Params.Width
+		}
+		if in.Params.Height != 0 {
+			fv.Height = &in.Params.Height
+		}
+	}
+
+	return fv
+}
+
+func newFileTexts(in db.VfsFileTexts) map[string]FileText {
+	if len(in) == 0 {
+		return nil
+	}
+
+	texts := make(map[string]FileText, len(in))
+	for lang, t := range in {
+		texts[lang] = FileText{Alt: t.Alt, Caption: t.Caption, Copyright: t.Copyright}
 	}
+
+	return texts
 }
 
 func NewFullFolder(in *db.VfsFolder, childFolders []db.VfsFolder) *Folder {
@@ -120,6 +331,104 @@ func NewFullFolder(in *db.VfsFolder, childFolders []db.VfsFolder) *Folder {
 	return f
 }
 
+// NewFolderTree returns root folder with nested sub folders from flat folder tree list ordered by level.
+func NewFolderTree(list []db.VfsFolderTreeItem) *Folder {
+	if len(list) == 0 {
+		return nil
+	}
+
+	children := make(map[int][]db.VfsFolderTreeItem)
+	for _, it := range list[1:] {
+		if it.ParentFolderID != nil {
+			children[*it.ParentFolderID] = append(children[*it.ParentFolderID], it)
+		}
+	}
+
+	var newTree func(it db.VfsFolderTreeItem) Folder
+	newTree = func(it db.VfsFolderTreeItem) Folder {
+		f := NewFolder(&it.VfsFolder)
+		f.Files, f.FilesSize = &it.Files, &it.FilesSize
+		f.IsDeleted = it.StatusID == db.StatusDeleted
+		for _, c := range children[it.ID] {
+			f.Folders = append(f.Folders, newTree(c))
+		}
+
+		return *f
+	}
+
+	root := newTree(list[0])
+	return &root
+}
+
+func NewFolderUsage(in db.VfsFolder, u db.FolderUsage) FolderUsage {
+	return FolderUsage{
+		ID:         in.ID,
+		Name:       in.Title,
+		Files:      u.Files,
+		FilesSize:  u.FilesSize,
+		QuotaFiles: in.QuotaFiles,
+		QuotaBytes: in.QuotaBytes,
+	}
+}
+
+func NewNamespaceUsage(in db.NamespaceUsage) NamespaceUsage {
+	return NamespaceUsage{
+		Namespace: in.Namespace,
+		Hashes:    in.Hashes,
+		FilesSize: in.FilesSize,
+	}
+}
+
+func NewHashColors(in *db.VfsHash) *HashColors {
+	if in == nil {
+		return nil
+	}
+
+	return &HashColors{
+		Hash:     in.Hash,
+		Width:    in.Width,
+		Height:   in.Height,
+		Blurhash: in.Blurhash,
+		Color:    in.Color,
+		Palette:  in.Palette,
+	}
+}
+
+func NewHashInfoResponse(in *db.VfsHash) *HashInfoResponse {
+	if in == nil {
+		return nil
+	}
+
+	return &HashInfoResponse{
+		Hash:      in.Hash,
+		Extension: in.Extension,
+		Size:      in.FileSize,
+		Width:     in.Width,
+		Height:    in.Height,
+		Blurhash:  in.Blurhash,
+		IndexedAt: in.IndexedAt,
+		Error:     in.Error,
+
+		Attempts:      in.Attempts,
+		NextAttemptAt: in.NextAttemptAt,
+	}
+}
+
+func NewSimilarHash(in *db.SimilarVfsHash, webPath string) *SimilarHash {
+	if in == nil {
+		return nil
+	}
+
+	return &SimilarHash{
+		Hash:      in.Hash,
+		Extension: in.Extension,
+		WebPath:   webPath,
+		Width:     in.Width,
+		Height:    in.Height,
+		Distance:  in.Distance,
+	}
+}
+
 func getSizeAndUnit(size float64) (newSize float64, unit string) {
 	_map := []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
 	base := 1000.0
@@ -156,3 +465,128 @@ type UrlByHashListResponse struct {
 	Hash    string `json:"hash"`
 	WebPath string `json:"webPath"`
 }
+
+// ToDB converts filter to db.ReindexFilter.
+func (f ReindexFilter) ToDB() db.ReindexFilter {
+	filter := db.ReindexFilter{
+		CreatedFrom:     f.CreatedFrom,
+		CreatedTo:       f.CreatedTo,
+		ErroredOnly:     f.ErroredOnly,
+		MissingBlurhash: f.MissingBlurhash,
+	}
+
+	if f.Namespace != nil {
+		ns := hashNamespace(*f.Namespace)
+		filter.Namespace = &ns
+	}
+
+	if f.Extension != nil {
+		ext := strings.ToLower(*f.Extension)
+		filter.Extension = &ext
+	}
+
+	return filter
+}
+
+// ToDB converts filter to db.FileSearch.
+func (f FileSearch) ToDB() *db.FileSearch {
+	return &db.FileSearch{
+		Query:        f.Query,
+		RootFolderID: f.RootFolderID,
+		MimeType:     f.MimeType,
+		Extension:    f.Extension,
+		MinSize:      f.MinSize,
+		MaxSize:      f.MaxSize,
+		CreatedFrom:  f.CreatedFrom,
+		CreatedTo:    f.CreatedTo,
+		MinWidth:     f.MinWidth,
+		MaxWidth:     f.MaxWidth,
+		MinHeight:    f.MinHeight,
+		MaxHeight:    f.MaxHeight,
+		Tags:         f.Tags,
+	}
+}
+
+// isValid checks filter ranges.
+func (f FileSearch) isValid() bool {
+	switch {
+	case f.MinSize != nil && f.MaxSize != nil && *f.MinSize > *f.MaxSize,
+		f.MinWidth != nil && f.MaxWidth != nil && *f.MinWidth > *f.MaxWidth,
+		f.MinHeight != nil && f.MaxHeight != nil && *f.MinHeight > *f.MaxHeight,
+		f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo):
+		return false
+	}
+
+	return true
+}
+
+// normalizeTags trims, lowercases and deduplicates tags. Returns false for empty or too long tag.
+func normalizeTags(tags []string) ([]string, bool) {
+	r := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || utf8.RuneCountInString(t) > maxTagLength {
+			return nil, false
+		}
+		if !slices.Contains(r, t) {
+			r = append(r, t)
+		}
+	}
+
+	return r, true
+}
+
+// isValidMeta checks that meta keys are not empty and not too long.
+func isValidMeta(meta map[string]string) bool {
+	for k := range meta {
+		if strings.TrimSpace(k) == "" || utf8.RuneCountInString(k) > maxMetaKeyLength {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isEmpty checks that all texts are empty.
+func (t FileText) isEmpty() bool {
+	return t.Alt == "" && t.Caption == "" && t.Copyright == ""
+}
+
+// isValid checks texts length.
+func (t FileText) isValid() bool {
+	for _, v := range []string{t.Alt, t.Caption, t.Copyright} {
+		if utf8.RuneCountInString(v) > maxFileTextLength {
+			return false
+		}
+	}
+
+	return true
+}
+
+// trim trims spaces in all texts.
+func (t FileText) trim() FileText {
+	return FileText{
+		Alt:       strings.TrimSpace(t.Alt),
+		Caption:   strings.TrimSpace(t.Caption),
+		Copyright: strings.TrimSpace(t.Copyright),
+	}
+}
+
+// ToDB converts text to db.VfsFileText.
+func (t FileText) ToDB() db.VfsFileText {
+	return db.VfsFileText{Alt: t.Alt, Caption: t.Caption, Copyright: t.Copyright}
+}
+
+// trashTimes returns formatted deleted at and purge at times.
+func trashTimes(deletedAt *time.Time, retention time.Duration) (string, *string) {
+	if deletedAt == nil {
+		return "", nil
+	}
+
+	if retention == 0 {
+		return deletedAt.Format(AtomTime), nil
+	}
+
+	purgeAt := deletedAt.Add(retention).Format(AtomTime)
+	return deletedAt.Format(AtomTime), &purgeAt
+}
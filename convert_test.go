@@ -0,0 +1,102 @@
+package vfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+)
+
+func Test_normalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+		ok   bool
+	}{
+		{name: "empty", tags: nil, want: []string{}, ok: true},
+		{name: "normalized", tags: []string{" Banner", "2026-campaign", "banner "}, want: []string{"banner", "2026-campaign"}, ok: true},
+		{name: "empty tag", tags: []string{"banner", " "}, ok: false},
+		{name: "too long", tags: []string{strings.Repeat("a", maxTagLength+1)}, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := normalizeTags(tt.tags)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeTags() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_isValidMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		meta map[string]string
+		want bool
+	}{
+		{name: "valid", meta: map[string]string{"alt": "Banner", "copyright": ""}, want: true},
+		{name: "empty key", meta: map[string]string{" ": "Banner"}, want: false},
+		{name: "too long key", meta: map[string]string{strings.Repeat("k", maxMetaKeyLength+1): "v"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isValidMeta(tt.meta); got != tt.want {
+				t.Errorf("isValidMeta() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_langRegex(t *testing.T) {
+	for lang, want := range map[string]bool{"en": true, "pt-BR": true, "zh-Hans": true, "EN": false, "english": false, "": false} {
+		if got := langRegex.MatchString(lang); got != want {
+			t.Errorf("langRegex.MatchString(%q) = %v, want %v", lang, got, want)
+		}
+	}
+}
+
+func Test_trashTimes(t *testing.T) {
+	deletedAt := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	d, p := trashTimes(&deletedAt, 0)
+	if d != "01.01.2026 10:00" || p != nil {
+		t.Errorf("trashTimes() = %v, %v", d, p)
+	}
+
+	d, p = trashTimes(&deletedAt, 24*time.Hour)
+	if d != "01.01.2026 10:00" || p == nil || *p != "02.01.2026 10:00" {
+		t.Errorf("trashTimes() = %v, %v", d, p)
+	}
+
+	if d, p = trashTimes(nil, time.Hour); d != "" || p != nil {
+		t.Errorf("trashTimes() = %v, %v", d, p)
+	}
+}
+
+func Test_NewFolderTree(t *testing.T) {
+	rootID, childID := 1, 2
+	list := []db.VfsFolderTreeItem{
+		{VfsFolder: db.VfsFolder{ID: rootID, Title: "root"}, Files: 1, FilesSize: 10},
+		{VfsFolder: db.VfsFolder{ID: childID, ParentFolderID: &rootID, Title: "a"}, Level: 1, Files: 2, FilesSize: 20},
+		{VfsFolder: db.VfsFolder{ID: 3, ParentFolderID: &rootID, Title: "b", StatusID: db.StatusDeleted}, Level: 1},
+		{VfsFolder: db.VfsFolder{ID: 4, ParentFolderID: &childID, Title: "c"}, Level: 2, Files: 3, FilesSize: 30},
+	}
+
+	f := NewFolderTree(list)
+	if f == nil || f.ID != rootID || *f.Files != 1 || *f.FilesSize != 10 || len(f.Folders) != 2 {
+		t.Fatalf("NewFolderTree() = %+v", f)
+	}
+	if a := f.Folders[0]; a.ID != childID || *a.Files != 2 || len(a.Folders) != 1 || a.Folders[0].ID != 4 || *a.Folders[0].Files != 3 {
+		t.Errorf("NewFolderTree() folder a = %+v", a)
+	}
+	if b := f.Folders[1]; b.ID != 3 || !b.IsDeleted || len(b.Folders) != 0 {
+		t.Errorf("NewFolderTree() folder b = %+v", b)
+	}
+
+	if f = NewFolderTree(nil); f != nil {
+		t.Errorf("NewFolderTree() = %+v, want nil", f)
+	}
+}
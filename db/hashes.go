@@ -2,14 +2,21 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"time"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
 )
 
-const tempTableName = `tmp_vfsHashes`
+const (
+	tempTableName = `tmp_vfsHashes`
+
+	// HashesChannel is a postgres NOTIFY channel for new vfsHashes, payload is a namespace.
+	HashesChannel = `vfsHashes`
+)
 
 // CreateTempHashesTable creates a temporary table for hashes.
 func (db DB) CreateTempHashesTable(ctx context.Context, tx *pg.Tx) error {
@@ -46,5 +53,43 @@ func (db DB) UpsertHashesTable(ctx context.Context, tx *pg.Tx) (int, time.Durati
 	if err != nil {
 		return 0, time.Duration(0), err
 	}
+
+	// wake up indexer after commit
+	if res.RowsAffected() > 0 {
+		if err = notifyHashes(ctx, tx, ""); err != nil {
+			return 0, time.Duration(0), err
+		}
+	}
+
 	return res.RowsAffected(), time.Since(t0), nil
 }
+
+// notifyHashes sends notification about new vfsHashes to HashesChannel.
+// Inside transaction notification is delivered on commit.
+func notifyHashes(ctx context.Context, db orm.DB, namespace string) error {
+	_, err := db.ExecContext(ctx, `SELECT pg_notify(?, ?)`, HashesChannel, namespace)
+	return err
+}
+
+// LastScanTime returns start time of last completed files scan for namespace or nil.
+func (db DB) LastScanTime(ctx context.Context, namespace string) (*time.Time, error) {
+	var t time.Time
+	query := fmt.Sprintf(`SELECT "scannedAt" FROM "%s" WHERE "namespace" = ?`, Tables.VfsScan.Name)
+	_, err := db.QueryOneContext(ctx, pg.Scan(&t), query, namespace)
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// SaveScanTime saves start time of completed files scan for namespaces.
+func (db DB) SaveScanTime(ctx context.Context, tx *pg.Tx, namespaces []string, scannedAt time.Time) error {
+	query := fmt.Sprintf(`INSERT INTO "%s" ("namespace", "scannedAt")
+	(SELECT unnest(?::text[]), ?)
+	ON CONFLICT ("namespace") DO UPDATE SET "scannedAt" = EXCLUDED."scannedAt"`, Tables.VfsScan.Name)
+	_, err := tx.ExecContext(ctx, query, pg.Array(namespaces), scannedAt)
+	return err
+}
@@ -10,40 +10,60 @@ import (
 
 var Columns = struct {
 	VfsFile struct {
-		ID, FolderID, Title, Path, Params, IsFavorite, MimeType, FileSize, FileExists, CreatedAt, StatusID string
+		ID, FolderID, Title, Path, Params, IsFavorite, MimeType, FileSize, FileExists, CreatedAt, StatusID, Meta, Texts, IsPublished, DeletedAt string
 
 		Folder string
 	}
 	VfsFolder struct {
-		ID, ParentFolderID, Title, IsFavorite, CreatedAt, StatusID string
+		ID, ParentFolderID, Title, IsFavorite, CreatedAt, StatusID, DeletedAt, QuotaBytes, QuotaFiles string
 
 		ParentFolder string
 	}
 	VfsHash struct {
-		Hash, Namespace, Extension, FileSize, Width, Height, Blurhash, CreatedAt, IndexedAt, Error string
+		Hash, Namespace, Extension, FileSize, Width, Height, Blurhash, CreatedAt, IndexedAt, Error, Params, Color, Palette, Phash, Attempts, NextAttemptAt string
+	}
+	VfsFileVersion struct {
+		ID, FileID, Path, Params, MimeType, FileSize, CreatedAt string
+
+		File string
+	}
+	VfsTag struct {
+		ID, Title, CreatedAt string
+	}
+	VfsFileTag struct {
+		FileID, TagID string
+
+		File, Tag string
+	}
+	VfsScan struct {
+		Namespace, ScannedAt string
 	}
 }{
 	VfsFile: struct {
-		ID, FolderID, Title, Path, Params, IsFavorite, MimeType, FileSize, FileExists, CreatedAt, StatusID string
+		ID, FolderID, Title, Path, Params, IsFavorite, MimeType, FileSize, FileExists, CreatedAt, StatusID, Meta, Texts, IsPublished, DeletedAt string
 
 		Folder string
 	}{
-		ID:         "fileId",
-		FolderID:   "folderId",
-		Title:      "title",
-		Path:       "path",
-		Params:     "params",
-		IsFavorite: "isFavorite",
-		MimeType:   "mimeType",
-		FileSize:   "fileSize",
-		FileExists: "fileExists",
-		CreatedAt:  "createdAt",
-		StatusID:   "statusId",
+		ID:          "fileId",
+		FolderID:    "folderId",
+		Title:       "title",
+		Path:        "path",
+		Params:      "params",
+		IsFavorite:  "isFavorite",
+		MimeType:    "mimeType",
+		FileSize:    "fileSize",
+		FileExists:  "fileExists",
+		CreatedAt:   "createdAt",
+		StatusID:    "statusId",
+		Meta:        "meta",
+		Texts:       "texts",
+		IsPublished: "isPublished",
+		DeletedAt:   "deletedAt",
 
 		Folder: "Folder",
 	},
 	VfsFolder: struct {
-		ID, ParentFolderID, Title, IsFavorite, CreatedAt, StatusID string
+		ID, ParentFolderID, Title, IsFavorite, CreatedAt, StatusID, DeletedAt, QuotaBytes, QuotaFiles string
 
 		ParentFolder string
 	}{
@@ -53,22 +73,70 @@ var Columns = struct {
 		IsFavorite:     "isFavorite",
 		CreatedAt:      "createdAt",
 		StatusID:       "statusId",
+		DeletedAt:      "deletedAt",
+		QuotaBytes:     "quotaBytes",
+		QuotaFiles:     "quotaFiles",
 
 		ParentFolder: "ParentFolder",
 	},
 	VfsHash: struct {
-		Hash, Namespace, Extension, FileSize, Width, Height, Blurhash, CreatedAt, IndexedAt, Error string
+		Hash, Namespace, Extension, FileSize, Width, Height, Blurhash, CreatedAt, IndexedAt, Error, Params, Color, Palette, Phash, Attempts, NextAttemptAt string
 	}{
-		Hash:      "hash",
-		Namespace: "namespace",
-		Extension: "extension",
+		Hash:          "hash",
+		Namespace:     "namespace",
+		Extension:     "extension",
+		FileSize:      "fileSize",
+		Width:         "width",
+		Height:        "height",
+		Blurhash:      "blurhash",
+		CreatedAt:     "createdAt",
+		IndexedAt:     "indexedAt",
+		Error:         "error",
+		Params:        "params",
+		Color:         "color",
+		Palette:       "palette",
+		Phash:         "phash",
+		Attempts:      "attempts",
+		NextAttemptAt: "nextAttemptAt",
+	},
+	VfsFileVersion: struct {
+		ID, FileID, Path, Params, MimeType, FileSize, CreatedAt string
+
+		File string
+	}{
+		ID:        "versionId",
+		FileID:    "fileId",
+		Path:      "path",
+		Params:    "params",
+		MimeType:  "mimeType",
 		FileSize:  "fileSize",
-		Width:     "width",
-		Height:    "height",
-		Blurhash:  "blurhash",
 		CreatedAt: "createdAt",
-		IndexedAt: "indexedAt",
-		Error:     "error",
+
+		File: "File",
+	},
+	VfsTag: struct {
+		ID, Title, CreatedAt string
+	}{
+		ID:        "tagId",
+		Title:     "title",
+		CreatedAt: "createdAt",
+	},
+	VfsFileTag: struct {
+		FileID, TagID string
+
+		File, Tag string
+	}{
+		FileID: "fileId",
+		TagID:  "tagId",
+
+		File: "File",
+		Tag:  "Tag",
+	},
+	VfsScan: struct {
+		Namespace, ScannedAt string
+	}{
+		Namespace: "namespace",
+		ScannedAt: "scannedAt",
 	},
 }
 
@@ -82,6 +150,18 @@ var Tables = struct {
 	VfsHash struct {
 		Name, Alias string
 	}
+	VfsFileVersion struct {
+		Name, Alias string
+	}
+	VfsTag struct {
+		Name, Alias string
+	}
+	VfsFileTag struct {
+		Name, Alias string
+	}
+	VfsScan struct {
+		Name, Alias string
+	}
 }{
 	VfsFile: struct {
 		Name, Alias string
@@ -101,22 +181,50 @@ var Tables = struct {
 		Name:  "vfsHashes",
 		Alias: "t",
 	},
+	VfsFileVersion: struct {
+		Name, Alias string
+	}{
+		Name:  "vfsFileVersions",
+		Alias: "t",
+	},
+	VfsTag: struct {
+		Name, Alias string
+	}{
+		Name:  "vfsTags",
+		Alias: "t",
+	},
+	VfsFileTag: struct {
+		Name, Alias string
+	}{
+		Name:  "vfsFileTags",
+		Alias: "t",
+	},
+	VfsScan: struct {
+		Name, Alias string
+	}{
+		Name:  "vfsScans",
+		Alias: "t",
+	},
 }
 
 type VfsFile struct {
 	tableName struct{} `pg:"vfsFiles,alias:t,discard_unknown_columns"`
 
-	ID         int            `pg:"fileId,pk"`
-	FolderID   int            `pg:"folderId,use_zero"`
-	Title      string         `pg:"title,use_zero"`
-	Path       string         `pg:"path,use_zero"`
-	Params     *VfsFileParams `pg:"params"`
-	IsFavorite *bool          `pg:"isFavorite"`
-	MimeType   string         `pg:"mimeType,use_zero"`
-	FileSize   *int           `pg:"fileSize"`
-	FileExists bool           `pg:"fileExists,use_zero"`
-	CreatedAt  time.Time      `pg:"createdAt,use_zero"`
-	StatusID   int            `pg:"statusId,use_zero"`
+	ID          int            `pg:"fileId,pk"`
+	FolderID    int            `pg:"folderId,use_zero"`
+	Title       string         `pg:"title,use_zero"`
+	Path        string         `pg:"path,use_zero"`
+	Params      *VfsFileParams `pg:"params"`
+	IsFavorite  *bool          `pg:"isFavorite"`
+	MimeType    string         `pg:"mimeType,use_zero"`
+	FileSize    *int           `pg:"fileSize"`
+	FileExists  bool           `pg:"fileExists,use_zero"`
+	CreatedAt   time.Time      `pg:"createdAt,use_zero"`
+	StatusID    int            `pg:"statusId,use_zero"`
+	Meta        VfsFileMeta    `pg:"meta"`
+	Texts       VfsFileTexts   `pg:"texts"`
+	IsPublished bool           `pg:"isPublished,use_zero"`
+	DeletedAt   *time.Time     `pg:"deletedAt"`
 
 	Folder *VfsFolder `pg:"fk:folderId,rel:has-one"`
 }
@@ -124,12 +232,15 @@ type VfsFile struct {
 type VfsFolder struct {
 	tableName struct{} `pg:"vfsFolders,alias:t,discard_unknown_columns"`
 
-	ID             int       `pg:"folderId,pk"`
-	ParentFolderID *int      `pg:"parentFolderId"`
-	Title          string    `pg:"title,use_zero"`
-	IsFavorite     *bool     `pg:"isFavorite"`
-	CreatedAt      time.Time `pg:"createdAt,use_zero"`
-	StatusID       int       `pg:"statusId,use_zero"`
+	ID             int        `pg:"folderId,pk"`
+	ParentFolderID *int       `pg:"parentFolderId"`
+	Title          string     `pg:"title,use_zero"`
+	IsFavorite     *bool      `pg:"isFavorite"`
+	CreatedAt      time.Time  `pg:"createdAt,use_zero"`
+	StatusID       int        `pg:"statusId,use_zero"`
+	DeletedAt      *time.Time `pg:"deletedAt"`
+	QuotaBytes     *int64     `pg:"quotaBytes"`
+	QuotaFiles     *int       `pg:"quotaFiles"`
 
 	ParentFolder *VfsFolder `pg:"fk:parentFolderId,rel:has-one"`
 }
@@ -137,14 +248,59 @@ type VfsFolder struct {
 type VfsHash struct {
 	tableName struct{} `pg:"vfsHashes,alias:t,discard_unknown_columns"`
 
-	Hash      string     `pg:"hash,pk"`
-	Namespace string     `pg:"namespace,pk"`
-	Extension string     `pg:"extension,use_zero"`
-	FileSize  int        `pg:"fileSize,use_zero"`
-	Width     int        `pg:"width,use_zero"`
-	Height    int        `pg:"height,use_zero"`
-	Blurhash  *string    `pg:"blurhash"`
-	CreatedAt time.Time  `pg:"createdAt,use_zero"`
-	IndexedAt *time.Time `pg:"indexedAt"`
-	Error     string     `pg:"error,use_zero"`
+	Hash          string         `pg:"hash,pk"`
+	Namespace     string         `pg:"namespace,pk"`
+	Extension     string         `pg:"extension,use_zero"`
+	FileSize      int            `pg:"fileSize,use_zero"`
+	Width         int            `pg:"width,use_zero"`
+	Height        int            `pg:"height,use_zero"`
+	Blurhash      *string        `pg:"blurhash"`
+	CreatedAt     time.Time      `pg:"createdAt,use_zero"`
+	IndexedAt     *time.Time     `pg:"indexedAt"`
+	Error         string         `pg:"error,use_zero"`
+	Params        *VfsHashParams `pg:"params"`
+	Color         *string        `pg:"color"`
+	Palette       []string       `pg:"palette,array"`
+	Phash         *int64         `pg:"phash"`
+	Attempts      int            `pg:"attempts,use_zero"`
+	NextAttemptAt *time.Time     `pg:"nextAttemptAt"`
+}
+
+type VfsFileVersion struct {
+	tableName struct{} `pg:"vfsFileVersions,alias:t,discard_unknown_columns"`
+
+	ID        int            `pg:"versionId,pk"`
+	FileID    int            `pg:"fileId,use_zero"`
+	Path      string         `pg:"path,use_zero"`
+	Params    *VfsFileParams `pg:"params"`
+	MimeType  string         `pg:"mimeType,use_zero"`
+	FileSize  *int           `pg:"fileSize"`
+	CreatedAt time.Time      `pg:"createdAt,use_zero"`
+
+	File *VfsFile `pg:"fk:fileId,rel:has-one"`
+}
+
+type VfsTag struct {
+	tableName struct{} `pg:"vfsTags,alias:t,discard_unknown_columns"`
+
+	ID        int       `pg:"tagId,pk"`
+	Title     string    `pg:"title,use_zero"`
+	CreatedAt time.Time `pg:"createdAt,use_zero"`
+}
+
+type VfsFileTag struct {
+	tableName struct{} `pg:"vfsFileTags,alias:t,discard_unknown_columns"`
+
+	FileID int `pg:"fileId,pk"`
+	TagID  int `pg:"tagId,pk"`
+
+	File *VfsFile `pg:"fk:fileId,rel:has-one"`
+	Tag  *VfsTag  `pg:"fk:tagId,rel:has-one"`
+}
+
+type VfsScan struct {
+	tableName struct{} `pg:"vfsScans,alias:t,discard_unknown_columns"`
+
+	Namespace string    `pg:"namespace,pk"`
+	ScannedAt time.Time `pg:"scannedAt,use_zero"`
 }
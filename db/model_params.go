@@ -4,3 +4,27 @@ type VfsFileParams struct {
 	Width  int `json:"width,omitempty"`
 	Height int `json:"height,omitempty"`
 }
+
+// VfsFileMeta is a custom key-value file metadata, e.g. alt text, copyright or source url.
+type VfsFileMeta map[string]string
+
+// VfsFileTexts is a localized file texts by language code, e.g. "en".
+type VfsFileTexts map[string]VfsFileText
+
+// VfsFileText is a file texts for one language.
+type VfsFileText struct {
+	Alt       string `json:"alt,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+	Copyright string `json:"copyright,omitempty"`
+}
+
+type VfsHashParams struct {
+	Duration  float64 `json:"duration,omitempty"`  // media duration in seconds
+	Codec     string  `json:"codec,omitempty"`     // media codec name
+	Poster    string  `json:"poster,omitempty"`    // relative path to video poster frame
+	Pages     int     `json:"pages,omitempty"`     // total document pages
+	Thumbnail string  `json:"thumbnail,omitempty"` // relative path to document first page thumbnail
+
+	// Extra is a custom data from additional indexers.
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
@@ -66,6 +66,7 @@ type VfsFileSearch struct {
 	FileExists    *bool
 	CreatedAt     *time.Time
 	StatusID      *int
+	IsPublished   *bool
 	IDs           []int
 	TitleILike    *string
 	PathILike     *string
@@ -109,6 +110,9 @@ func (vfs *VfsFileSearch) Apply(query *orm.Query) *orm.Query {
 	if vfs.StatusID != nil {
 		vfs.where(query, Tables.VfsFile.Alias, Columns.VfsFile.StatusID, vfs.StatusID)
 	}
+	if vfs.IsPublished != nil {
+		vfs.where(query, Tables.VfsFile.Alias, Columns.VfsFile.IsPublished, vfs.IsPublished)
+	}
 	if len(vfs.IDs) > 0 {
 		Filter{Columns.VfsFile.ID, vfs.IDs, SearchTypeArray, false}.Apply(query)
 	}
@@ -205,6 +209,11 @@ type VfsHashSearch struct {
 	CreatedAt      *time.Time
 	IndexedAt      *time.Time
 	Error          *string
+	Params         *VfsHashParams
+	Color          *string
+	Phash          *int64
+	Attempts       *int
+	NextAttemptAt  *time.Time
 	Hashes         []string
 	HashILike      *string
 	Namespaces     []string
@@ -248,6 +257,21 @@ func (vhs *VfsHashSearch) Apply(query *orm.Query) *orm.Query {
 	if vhs.Error != nil {
 		vhs.where(query, Tables.VfsHash.Alias, Columns.VfsHash.Error, vhs.Error)
 	}
+	if vhs.Params != nil {
+		vhs.where(query, Tables.VfsHash.Alias, Columns.VfsHash.Params, vhs.Params)
+	}
+	if vhs.Color != nil {
+		vhs.where(query, Tables.VfsHash.Alias, Columns.VfsHash.Color, vhs.Color)
+	}
+	if vhs.Phash != nil {
+		vhs.where(query, Tables.VfsHash.Alias, Columns.VfsHash.Phash, vhs.Phash)
+	}
+	if vhs.Attempts != nil {
+		vhs.where(query, Tables.VfsHash.Alias, Columns.VfsHash.Attempts, vhs.Attempts)
+	}
+	if vhs.NextAttemptAt != nil {
+		vhs.where(query, Tables.VfsHash.Alias, Columns.VfsHash.NextAttemptAt, vhs.NextAttemptAt)
+	}
 	if len(vhs.Hashes) > 0 {
 		Filter{Columns.VfsHash.Hash, vhs.Hashes, SearchTypeArray, false}.Apply(query)
 	}
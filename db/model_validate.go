@@ -51,3 +51,37 @@ func (vh VfsHash) Validate() (errors map[string]string, valid bool) {
 
 	return errors, len(errors) == 0
 }
+
+func (vfv VfsFileVersion) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(vfv.Path) > 255 {
+		errors[Columns.VfsFileVersion.Path] = ErrMaxLength
+	}
+
+	if utf8.RuneCountInString(vfv.MimeType) > 255 {
+		errors[Columns.VfsFileVersion.MimeType] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (vt VfsTag) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(vt.Title) > 64 {
+		errors[Columns.VfsTag.Title] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
+
+func (vs VfsScan) Validate() (errors map[string]string, valid bool) {
+	errors = map[string]string{}
+
+	if utf8.RuneCountInString(vs.Namespace) > 32 {
+		errors[Columns.VfsScan.Namespace] = ErrMaxLength
+	}
+
+	return errors, len(errors) == 0
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HashExtensionStats is a hashes stats by namespace and extension.
+type HashExtensionStats struct {
+	Namespace string  `pg:"namespace"`
+	Extension string  `pg:"extension"`
+	Hashes    int     `pg:"hashes"`
+	FilesSize int64   `pg:"filesSize"`
+	AvgWidth  float64 `pg:"avgWidth"`  // average width of hashes with dimensions
+	AvgHeight float64 `pg:"avgHeight"` // average height of hashes with dimensions
+	Indexed   int     `pg:"indexed"`
+	Pending   int     `pg:"pending"`
+}
+
+// HashDailyGrowth is a total hashes and hashes size created in namespace per day.
+type HashDailyGrowth struct {
+	Namespace string    `pg:"namespace"`
+	Day       time.Time `pg:"day"`
+	Hashes    int       `pg:"hashes"`
+	FilesSize int64     `pg:"filesSize"`
+}
+
+// HashExtensionStats returns hashes stats grouped by namespace and extension.
+func (vr VfsRepo) HashExtensionStats(ctx context.Context) (list []HashExtensionStats, err error) {
+	query := fmt.Sprintf(`SELECT "namespace", "extension",
+	count(*) AS "hashes",
+	coalesce(sum("fileSize"), 0) AS "filesSize",
+	coalesce(avg("width") FILTER (WHERE "width" > 0), 0) AS "avgWidth",
+	coalesce(avg("height") FILTER (WHERE "height" > 0), 0) AS "avgHeight",
+	count(*) FILTER (WHERE "indexedAt" IS NOT NULL) AS "indexed",
+	count(*) FILTER (WHERE "indexedAt" IS NULL) AS "pending"
+FROM "%s"
+GROUP BY "namespace", "extension"
+ORDER BY "namespace", "extension"`, Tables.VfsHash.Name)
+	_, err = vr.db.QueryContext(ctx, &list, query)
+
+	return
+}
+
+// HashDailyGrowth returns hashes created since from grouped by namespace and day.
+func (vr VfsRepo) HashDailyGrowth(ctx context.Context, from time.Time) (list []HashDailyGrowth, err error) {
+	query := fmt.Sprintf(`SELECT "namespace", date_trunc('day', "createdAt") AS "day",
+	count(*) AS "hashes",
+	coalesce(sum("fileSize"), 0) AS "filesSize"
+FROM "%s"
+WHERE "createdAt" >= ?
+GROUP BY "namespace", "day"
+ORDER BY "namespace", "day"`, Tables.VfsHash.Name)
+	_, err = vr.db.QueryContext(ctx, &list, query, from)
+
+	return
+}
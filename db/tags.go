@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// FileTag is a file tag title.
+type FileTag struct {
+	FileID int    `pg:"fileId"`
+	Title  string `pg:"title"`
+}
+
+// TagCount is a tag title with total tagged files.
+type TagCount struct {
+	Title string `pg:"title"`
+	Files int    `pg:"files"`
+}
+
+// FileTags returns tags for files sorted by title.
+func (vr VfsRepo) FileTags(ctx context.Context, fileIDs []int) (map[int][]string, error) {
+	tags := make(map[int][]string, len(fileIDs))
+	if len(fileIDs) == 0 {
+		return tags, nil
+	}
+
+	var list []FileTag
+	query := fmt.Sprintf(`SELECT ft."fileId", t."title" FROM "%s" ft
+	JOIN "%s" t ON t."tagId" = ft."tagId"
+WHERE ft."fileId" IN (?)
+ORDER BY t."title"`, Tables.VfsFileTag.Name, Tables.VfsTag.Name)
+	if _, err := vr.db.QueryContext(ctx, &list, query, pg.In(fileIDs)); err != nil {
+		return nil, err
+	}
+
+	for _, ft := range list {
+		tags[ft.FileID] = append(tags[ft.FileID], ft.Title)
+	}
+
+	return tags, nil
+}
+
+// Tags returns all tags with total not deleted tagged files sorted by title.
+func (vr VfsRepo) Tags(ctx context.Context) (list []TagCount, err error) {
+	query := fmt.Sprintf(`SELECT t."title", count(f."fileId") AS "files" FROM "%s" t
+	LEFT JOIN "%s" ft ON ft."tagId" = t."tagId"
+	LEFT JOIN "%s" f ON f."fileId" = ft."fileId" AND f."statusId" <> ?
+GROUP BY t."title"
+ORDER BY t."title"`, Tables.VfsTag.Name, Tables.VfsFileTag.Name, Tables.VfsFile.Name)
+	_, err = vr.db.QueryContext(ctx, &list, query, StatusDeleted)
+
+	return
+}
+
+// AddFileTags creates missing tags and adds them to files. Returns total added file tags.
+func (vr VfsRepo) AddFileTags(ctx context.Context, fileIDs []int64, tags []string) (int, error) {
+	if len(fileIDs) == 0 || len(tags) == 0 {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf(`INSERT INTO "%s" ("title") SELECT unnest(?::text[]) ON CONFLICT ("title") DO NOTHING`, Tables.VfsTag.Name)
+	if _, err := vr.db.ExecContext(ctx, query, pg.Array(tags)); err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf(`INSERT INTO "%s" ("fileId", "tagId")
+SELECT f."fileId", t."tagId" FROM "%s" f, "%s" t
+WHERE f."fileId" IN (?) AND t."title" IN (?)
+ON CONFLICT DO NOTHING`, Tables.VfsFileTag.Name, Tables.VfsFile.Name, Tables.VfsTag.Name)
+	res, err := vr.db.ExecContext(ctx, query, pg.Ints(fileIDs), pg.In(tags))
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// RemoveFileTags removes tags from files. Returns total removed file tags.
+func (vr VfsRepo) RemoveFileTags(ctx context.Context, fileIDs []int64, tags []string) (int, error) {
+	if len(fileIDs) == 0 || len(tags) == 0 {
+		return 0, nil
+	}
+
+	query := fmt.Sprintf(`DELETE FROM "%s"
+WHERE "fileId" IN (?) AND "tagId" IN (SELECT "tagId" FROM "%s" WHERE "title" IN (?))`, Tables.VfsFileTag.Name, Tables.VfsTag.Name)
+	res, err := vr.db.ExecContext(ctx, query, pg.Ints(fileIDs), pg.In(tags))
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// SetFilesMeta merges meta into files metadata. Returns total updated files.
+func (vr VfsRepo) SetFilesMeta(ctx context.Context, fileIDs []int64, meta VfsFileMeta) (int, error) {
+	if len(fileIDs) == 0 || len(meta) == 0 {
+		return 0, nil
+	}
+
+	res, err := vr.db.ModelContext(ctx, (*VfsFile)(nil)).
+		Set(`? = coalesce(?, '{}'::jsonb) || ?::jsonb`, pg.Ident(Columns.VfsFile.Meta), pg.Ident(Columns.VfsFile.Meta), meta).
+		Where(`? IN (?)`, pg.Ident(Columns.VfsFile.ID), pg.Ints(fileIDs)).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// RemoveFilesMeta removes keys from files metadata. Returns total updated files.
+func (vr VfsRepo) RemoveFilesMeta(ctx context.Context, fileIDs []int64, keys []string) (int, error) {
+	if len(fileIDs) == 0 || len(keys) == 0 {
+		return 0, nil
+	}
+
+	res, err := vr.db.ModelContext(ctx, (*VfsFile)(nil)).
+		Set(`? = ? - ?::text[]`, pg.Ident(Columns.VfsFile.Meta), pg.Ident(Columns.VfsFile.Meta), pg.Array(keys)).
+		Where(`? IN (?)`, pg.Ident(Columns.VfsFile.ID), pg.Ints(fileIDs)).
+		Where(`jsonb_exists_any(?, ?::text[])`, pg.Ident(Columns.VfsFile.Meta), pg.Array(keys)).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// filesWithTags filters files which have all tags.
+func filesWithTags(q *orm.Query, tags []string) *orm.Query {
+	query := fmt.Sprintf(`? IN (SELECT ft."fileId" FROM "%s" ft
+	JOIN "%s" t ON t."tagId" = ft."tagId"
+WHERE t."title" IN (?)
+GROUP BY ft."fileId"
+HAVING count(*) = ?)`, Tables.VfsFileTag.Name, Tables.VfsTag.Name)
+
+	return q.Where(query, pg.Ident(Columns.VfsFile.ID), pg.In(tags), len(tags))
+}
+
+// WithTags filters files which have all tags.
+func (vfs *VfsFileSearch) WithTags(tags []string) *VfsFileSearch {
+	if len(tags) > 0 {
+		vfs.WithApply(func(q *orm.Query) (*orm.Query, error) {
+			return filesWithTags(q, tags), nil
+		})
+	}
+
+	return vfs
+}
@@ -0,0 +1,175 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// DeleteFolderTree marks folder, its sub folders and their files as deleted. Returns total deleted folders and files.
+func (vr VfsRepo) DeleteFolderTree(ctx context.Context, folderID int, deletedAt time.Time) (folders, files int, err error) {
+	var ids []int
+	_, err = vr.db.QueryContext(ctx, pg.Scan(pg.Array(&ids)), `
+WITH RECURSIVE r AS (
+   SELECT "folderId" FROM "vfsFolders" WHERE "folderId" = ?0 AND "statusId" <> ?1
+   UNION SELECT ff."folderId" FROM "vfsFolders" ff
+ 		JOIN r ON ff."parentFolderId" = r."folderId"
+   WHERE ff."statusId" <> ?1
+), d AS (
+   UPDATE "vfsFolders" SET "statusId" = ?1, "deletedAt" = ?2
+   WHERE "folderId" IN (SELECT "folderId" FROM r)
+   RETURNING "folderId"
+)
+SELECT coalesce(array_agg("folderId"), '{}') FROM d`, folderID, StatusDeleted, deletedAt)
+	if err != nil || len(ids) == 0 {
+		return 0, 0, err
+	}
+
+	res, err := vr.db.ModelContext(ctx, &VfsFile{StatusID: StatusDeleted, DeletedAt: &deletedAt}).
+		Column(Columns.VfsFile.StatusID, Columns.VfsFile.DeletedAt).
+		Where(`? IN (?)`, pg.Ident(Columns.VfsFile.FolderID), pg.In(ids)).
+		Where(`? <> ?`, pg.Ident(Columns.VfsFile.StatusID), StatusDeleted).
+		Update()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(ids), res.RowsAffected(), nil
+}
+
+// DeletedVfsFolder returns deleted folder by id or nil.
+func (vr VfsRepo) DeletedVfsFolder(ctx context.Context, folderID int) (*VfsFolder, error) {
+	f := &VfsFolder{}
+	err := vr.db.ModelContext(ctx, f).
+		Where(`? = ?`, pg.Ident(Columns.VfsFolder.ID), folderID).
+		Where(`? = ?`, pg.Ident(Columns.VfsFolder.StatusID), StatusDeleted).
+		Select()
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	}
+
+	return f, err
+}
+
+// TrashFolders returns deleted folders with not deleted parent folder, last deleted first.
+func (vr VfsRepo) TrashFolders(ctx context.Context) (list []VfsFolder, err error) {
+	err = vr.db.ModelContext(ctx, &list).
+		Where(`? = ?`, pg.Ident(Columns.VfsFolder.StatusID), StatusDeleted).
+		Where(`NOT EXISTS (SELECT 1 FROM "vfsFolders" p WHERE p."folderId" = ?TableAlias.? AND p."statusId" = ?)`, pg.Ident(Columns.VfsFolder.ParentFolderID), StatusDeleted).
+		OrderExpr(`? DESC`, pg.Ident(Columns.VfsFolder.DeletedAt)).
+		Select()
+
+	return
+}
+
+// TrashFiles returns deleted files from not deleted folders, last deleted first, and total count.
+// Files from deleted folders are returned with folder, see TrashFolders.
+func (vr VfsRepo) TrashFiles(ctx context.Context, pager Pager) (list []VfsFile, count int, err error) {
+	q := vr.db.ModelContext(ctx, &list).
+		Where(`? = ?`, pg.Ident(Columns.VfsFile.StatusID), StatusDeleted).
+		Where(`? IN (SELECT "folderId" FROM "vfsFolders" WHERE "statusId" <> ?)`, pg.Ident(Columns.VfsFile.FolderID), StatusDeleted).
+		OrderExpr(`? DESC`, pg.Ident(Columns.VfsFile.DeletedAt))
+	count, err = pager.Apply(q).SelectAndCount()
+
+	return
+}
+
+// RestoreVfsFiles restores deleted files from not deleted folders. Returns total restored files.
+func (vr VfsRepo) RestoreVfsFiles(ctx context.Context, fileIDs []int64) (int, error) {
+	res, err := vr.db.ModelContext(ctx, &VfsFile{StatusID: StatusEnabled}).
+		Column(Columns.VfsFile.StatusID, Columns.VfsFile.DeletedAt).
+		Where(`? IN (?)`, pg.Ident(Columns.VfsFile.ID), pg.Ints(fileIDs)).
+		Where(`? = ?`, pg.Ident(Columns.VfsFile.StatusID), StatusDeleted).
+		Where(`? IN (SELECT "folderId" FROM "vfsFolders" WHERE "statusId" <> ?)`, pg.Ident(Columns.VfsFile.FolderID), StatusDeleted).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// RestoreFolderTree restores deleted folder with sub folders and files deleted at the same time.
+// Items deleted before folder stay in trash. Returns total restored folders and files.
+func (vr VfsRepo) RestoreFolderTree(ctx context.Context, folder *VfsFolder) (folders, files int, err error) {
+	if folder.DeletedAt == nil {
+		return 0, 0, nil
+	}
+
+	var ids []int
+	_, err = vr.db.QueryContext(ctx, pg.Scan(pg.Array(&ids)), `
+WITH RECURSIVE r AS (
+   SELECT "folderId" FROM "vfsFolders" WHERE "folderId" = ?0 AND "statusId" = ?1 AND "deletedAt" = ?2
+   UNION SELECT ff."folderId" FROM "vfsFolders" ff
+ 		JOIN r ON ff."parentFolderId" = r."folderId"
+   WHERE ff."statusId" = ?1 AND ff."deletedAt" = ?2
+), u AS (
+   UPDATE "vfsFolders" SET "statusId" = ?3, "deletedAt" = NULL
+   WHERE "folderId" IN (SELECT "folderId" FROM r)
+   RETURNING "folderId"
+)
+SELECT coalesce(array_agg("folderId"), '{}') FROM u`, folder.ID, StatusDeleted, *folder.DeletedAt, StatusEnabled)
+	if err != nil || len(ids) == 0 {
+		return 0, 0, err
+	}
+
+	res, err := vr.db.ModelContext(ctx, &VfsFile{StatusID: StatusEnabled}).
+		Column(Columns.VfsFile.StatusID, Columns.VfsFile.DeletedAt).
+		Where(`? IN (?)`, pg.Ident(Columns.VfsFile.FolderID), pg.In(ids)).
+		Where(`? = ?`, pg.Ident(Columns.VfsFile.StatusID), StatusDeleted).
+		Where(`? = ?`, pg.Ident(Columns.VfsFile.DeletedAt), *folder.DeletedAt).
+		Update()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(ids), res.RowsAffected(), nil
+}
+
+// PurgedPath is a path of removed file or file version.
+type PurgedPath struct {
+	Path      string `pg:"path"`
+	IsVersion bool   `pg:"isVersion"`
+}
+
+// PurgeFiles removes at most limit files deleted before time from DB.
+// Returns total removed files and paths of removed files and their versions.
+func (vr VfsRepo) PurgeFiles(ctx context.Context, before time.Time, limit int) (files int, paths []PurgedPath, err error) {
+	_, err = vr.db.QueryContext(ctx, &paths, `
+WITH f AS (
+   SELECT "fileId" FROM "vfsFiles" WHERE "statusId" = ?0 AND "deletedAt" < ?1
+   ORDER BY "fileId" LIMIT ?2 FOR UPDATE SKIP LOCKED
+), v AS (
+   DELETE FROM "vfsFileVersions" WHERE "fileId" IN (SELECT "fileId" FROM f) RETURNING "path"
+), d AS (
+   DELETE FROM "vfsFiles" WHERE "fileId" IN (SELECT "fileId" FROM f) RETURNING "path"
+)
+SELECT "path", false AS "isVersion" FROM d UNION ALL SELECT "path", true FROM v`, StatusDeleted, before, limit)
+
+	for _, p := range paths {
+		if !p.IsVersion {
+			files++
+		}
+	}
+
+	return
+}
+
+// PurgeFolders removes folders deleted before time without sub folders and files from DB. Returns total removed folders.
+func (vr VfsRepo) PurgeFolders(ctx context.Context, before time.Time) (total int, err error) {
+	// remove leaf folders until nothing is removed
+	for {
+		res, err := vr.db.ExecContext(ctx, `
+DELETE FROM "vfsFolders" t WHERE t."statusId" = ?0 AND t."deletedAt" < ?1
+   AND NOT EXISTS (SELECT 1 FROM "vfsFolders" c WHERE c."parentFolderId" = t."folderId")
+   AND NOT EXISTS (SELECT 1 FROM "vfsFiles" f WHERE f."folderId" = t."folderId")`, StatusDeleted, before)
+		if err != nil {
+			return total, err
+		} else if res.RowsAffected() == 0 {
+			return total, nil
+		}
+		total += res.RowsAffected()
+	}
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// FolderUsage is a total not deleted files and files size in folder subtree.
+type FolderUsage struct {
+	FolderID  int   `pg:"folderId"`
+	Files     int   `pg:"files"`
+	FilesSize int64 `pg:"filesSize"`
+}
+
+// NamespaceUsage is a total hashes and hashes size in namespace.
+type NamespaceUsage struct {
+	Namespace string `pg:"namespace"`
+	Hashes    int    `pg:"hashes"`
+	FilesSize int64  `pg:"filesSize"`
+}
+
+// FoldersUsage returns usage of each folder subtree by folder id.
+func (vr VfsRepo) FoldersUsage(ctx context.Context, folderIDs []int) (map[int]FolderUsage, error) {
+	usage := make(map[int]FolderUsage, len(folderIDs))
+	if len(folderIDs) == 0 {
+		return usage, nil
+	}
+
+	var list []FolderUsage
+	query := fmt.Sprintf(`
+WITH RECURSIVE t AS (
+   SELECT "folderId" AS "rootId", "folderId" FROM "%[1]s"
+   WHERE "folderId" IN (?0)
+   UNION ALL SELECT t."rootId", ff."folderId"
+   FROM "%[1]s" ff
+ 		JOIN t ON ff."parentFolderId" = t."folderId"
+   WHERE ff."statusId" <> ?1
+)
+SELECT t."rootId" AS "folderId", count(f."fileId") AS "files", coalesce(sum(f."fileSize"), 0) AS "filesSize"
+FROM t LEFT JOIN "%[2]s" f ON f."folderId" = t."folderId" AND f."statusId" <> ?1
+GROUP BY t."rootId"`, Tables.VfsFolder.Name, Tables.VfsFile.Name)
+	if _, err := vr.db.QueryContext(ctx, &list, query, pg.In(folderIDs), StatusDeleted); err != nil {
+		return nil, err
+	}
+
+	for _, u := range list {
+		usage[u.FolderID] = u
+	}
+
+	return usage, nil
+}
+
+// LockVfsFolders locks folders with FOR UPDATE until the end of transaction.
+func (vr VfsRepo) LockVfsFolders(ctx context.Context, folderIDs []int) error {
+	if len(folderIDs) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`SELECT "folderId" FROM "%s" WHERE "folderId" IN (?0) ORDER BY "folderId" FOR UPDATE`, Tables.VfsFolder.Name)
+	_, err := vr.db.ExecContext(ctx, query, pg.In(folderIDs))
+
+	return err
+}
+
+// NamespacesUsage returns total hashes and hashes size per namespace sorted by namespace.
+func (vr VfsRepo) NamespacesUsage(ctx context.Context) (list []NamespaceUsage, err error) {
+	query := fmt.Sprintf(`SELECT "namespace", count(*) AS "hashes", coalesce(sum("fileSize"), 0) AS "filesSize" FROM "%s"
+GROUP BY "namespace"
+ORDER BY "namespace"`, Tables.VfsHash.Name)
+	_, err = vr.db.QueryContext(ctx, &list, query)
+
+	return
+}
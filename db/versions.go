@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// versionColumns is a list of file content columns copied between vfsFiles and vfsFileVersions.
+const versionColumns = `"path", "params", "mimeType", "fileSize"`
+
+// FileVersions returns prior versions of file, newest first.
+func (vr VfsRepo) FileVersions(ctx context.Context, fileID int) (list []VfsFileVersion, err error) {
+	err = vr.db.ModelContext(ctx, &list).
+		Where(`"fileId" = ?`, fileID).
+		OrderExpr(`? DESC`, pg.Ident("versionId")).
+		Select()
+
+	return
+}
+
+// FileVersionPaths returns paths of all prior file versions.
+func (vr VfsRepo) FileVersionPaths(ctx context.Context) (paths []string, err error) {
+	err = vr.db.ModelContext(ctx, (*VfsFileVersion)(nil)).
+		Column("path").
+		Select(&paths)
+
+	return
+}
+
+// ReplaceVfsFile saves current file content as prior version and updates file path, params, mime type and size.
+// Both changes are made by single statement. Returns false if file is not found.
+func (vr VfsRepo) ReplaceVfsFile(ctx context.Context, f *VfsFile) (bool, error) {
+	query := fmt.Sprintf(`WITH v AS (
+	INSERT INTO "%[1]s" ("fileId", %[2]s)
+	SELECT "fileId", %[2]s FROM "%[3]s" WHERE "fileId" = ?0
+)
+UPDATE "%[3]s" SET "path" = ?1, "params" = ?2, "mimeType" = ?3, "fileSize" = ?4, "fileExists" = true
+WHERE "fileId" = ?0`, Tables.VfsFileVersion.Name, versionColumns, Tables.VfsFile.Name)
+
+	res, err := vr.db.ExecContext(ctx, query, f.ID, f.Path, f.Params, f.MimeType, f.FileSize)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// RollbackVfsFile restores file content from prior version. Current content is saved as new prior version
+// and restored version is removed. Returns false if version of file is not found.
+func (vr VfsRepo) RollbackVfsFile(ctx context.Context, fileID, versionID int) (bool, error) {
+	query := fmt.Sprintf(`WITH old AS (
+	SELECT * FROM "%[1]s" WHERE "versionId" = ?1 AND "fileId" = ?0
+), cur AS (
+	INSERT INTO "%[1]s" ("fileId", %[2]s)
+	SELECT "fileId", %[2]s FROM "%[3]s" WHERE "fileId" = ?0 AND EXISTS (SELECT 1 FROM old)
+), del AS (
+	DELETE FROM "%[1]s" WHERE "versionId" IN (SELECT "versionId" FROM old)
+)
+UPDATE "%[3]s" f SET "path" = old."path", "params" = old."params", "mimeType" = old."mimeType", "fileSize" = old."fileSize", "fileExists" = true
+FROM old
+WHERE f."fileId" = old."fileId"`, Tables.VfsFileVersion.Name, versionColumns, Tables.VfsFile.Name)
+
+	res, err := vr.db.ExecContext(ctx, query, fileID, versionID)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
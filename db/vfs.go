@@ -42,6 +42,20 @@ func (vr VfsRepo) WithTransaction(tx *pg.Tx) VfsRepo {
 	return vr
 }
 
+// RunInTransaction runs fn with repo wrapped in transaction, transaction of repo is reused if repo is already in transaction.
+func (vr VfsRepo) RunInTransaction(ctx context.Context, fn func(repo VfsRepo) error) error {
+	t, ok := vr.db.(interface {
+		RunInTransaction(context.Context, func(*pg.Tx) error) error
+	})
+	if !ok {
+		return fn(vr)
+	}
+
+	return t.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		return fn(vr.WithTransaction(tx))
+	})
+}
+
 // WithEnabledOnly is a function that adds "statusId"=1 as base filter.
 func (vr VfsRepo) WithEnabledOnly() VfsRepo {
 	f := make(map[string][]Filter, len(vr.filters))
@@ -2,9 +2,14 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
 )
 
 func (vr VfsRepo) FolderBranch(ctx context.Context, folderID int) (list []VfsFolder, err error) {
@@ -21,6 +26,36 @@ SELECT * FROM r ORDER by level DESC`, folderID)
 	return
 }
 
+// VfsFolderTreeItem is a folder with its level in tree and total not deleted files in folder.
+type VfsFolderTreeItem struct {
+	VfsFolder
+
+	Level     int   `pg:"level"`
+	Files     int   `pg:"files"`
+	FilesSize int64 `pg:"filesSize"`
+}
+
+// FolderTree returns folder with sub folders down to depth level ordered by level and title, 0 depth is unlimited.
+// Deleted folders are returned with files in trash if withDeleted is set.
+func (vr VfsRepo) FolderTree(ctx context.Context, folderID, depth int, withDeleted bool) (list []VfsFolderTreeItem, err error) {
+	_, err = vr.db.QueryContext(ctx, &list, `
+WITH RECURSIVE r AS (
+   SELECT *, 0 AS level FROM "vfsFolders"
+   WHERE "folderId" = ?0 AND (?2 OR "statusId" <> ?1)
+   UNION SELECT ff.*, r.level + 1 AS level
+   FROM "vfsFolders" ff
+ 		JOIN r ON ff."parentFolderId" = r."folderId"
+   WHERE (?2 OR ff."statusId" <> ?1) AND (?3 = 0 OR r.level < ?3)
+)
+SELECT r.*, s."files", s."filesSize" FROM r, LATERAL (
+   SELECT count(*) AS "files", coalesce(sum(f."fileSize"), 0) AS "filesSize" FROM "vfsFiles" f
+   WHERE f."folderId" = r."folderId" AND (f."statusId" <> ?1 OR r."statusId" = ?1)
+) s
+ORDER BY level, r."title"`, folderID, StatusDeleted, withDeleted, depth)
+
+	return
+}
+
 func (vfs *VfsFileSearch) WithQuery(query *string) *VfsFileSearch {
 	if query != nil && *query != "" {
 		vfs.TitleILike = query
@@ -29,6 +64,15 @@ func (vfs *VfsFileSearch) WithQuery(query *string) *VfsFileSearch {
 	return vfs
 }
 
+// WithFolderIDs filters files by folders.
+func (vfs *VfsFileSearch) WithFolderIDs(folderIDs []int) *VfsFileSearch {
+	vfs.WithApply(func(q *orm.Query) (*orm.Query, error) {
+		return q.Where(`? IN (?)`, pg.Ident(Columns.VfsFile.FolderID), pg.In(folderIDs)), nil
+	})
+
+	return vfs
+}
+
 func (vr VfsRepo) NextFileID() (int, error) {
 	var maxFileID int
 	_, err := vr.db.Query(pg.Scan(&maxFileID), `select nextval('"vfsFiles_fileId_seq"')`)
@@ -50,9 +94,11 @@ func (vr VfsRepo) UpdateFilesFolder(ctx context.Context, fileIDs []int64, newFol
 }
 
 func (vr VfsRepo) DeleteVfsFiles(ctx context.Context, fileIDs []int64) (bool, error) {
-	q := vr.db.ModelContext(ctx, &VfsFile{StatusID: StatusDeleted}).
-		Column(Columns.VfsFile.StatusID).
-		Where("? IN (?)", pg.Ident(Columns.VfsFile.ID), pg.Ints(fileIDs))
+	now := time.Now()
+	q := vr.db.ModelContext(ctx, &VfsFile{StatusID: StatusDeleted, DeletedAt: &now}).
+		Column(Columns.VfsFile.StatusID, Columns.VfsFile.DeletedAt).
+		Where("? IN (?)", pg.Ident(Columns.VfsFile.ID), pg.Ints(fileIDs)).
+		Where("? <> ?", pg.Ident(Columns.VfsFile.StatusID), StatusDeleted)
 
 	res, err := q.Update()
 	if err != nil {
@@ -62,15 +108,77 @@ func (vr VfsRepo) DeleteVfsFiles(ctx context.Context, fileIDs []int64) (bool, er
 	return res.RowsAffected() > 0, err
 }
 
+// VfsFilePath is a file path with file state for files scan.
+type VfsFilePath struct {
+	ID         int    `pg:"fileId"`
+	Path       string `pg:"path"`
+	FileExists bool   `pg:"fileExists"`
+	StatusID   int    `pg:"statusId"`
+	IsNew      bool   `pg:"isNew"` // file is created since time passed to VfsFilePaths
+}
+
+// VfsFilePaths returns paths of all files including deleted ones. Files created since createdSince are marked as new.
+func (vr VfsRepo) VfsFilePaths(ctx context.Context, createdSince time.Time) (list []VfsFilePath, err error) {
+	query := fmt.Sprintf(`SELECT "fileId", "path", "fileExists", "statusId", "createdAt" >= ? AS "isNew" FROM "%s"`, Tables.VfsFile.Name)
+	_, err = vr.db.QueryContext(ctx, &list, query, createdSince)
+
+	return
+}
+
+// VfsFilePathExists checks that path is used by any file including deleted ones or by file version.
+func (vr VfsRepo) VfsFilePathExists(ctx context.Context, path string) (exists bool, err error) {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM "%s" WHERE "path" = ?0) OR EXISTS (SELECT 1 FROM "%s" WHERE "path" = ?0)`,
+		Tables.VfsFile.Name, Tables.VfsFileVersion.Name)
+	_, err = vr.db.QueryOneContext(ctx, pg.Scan(&exists), query, path)
+
+	return
+}
+
+// SetVfsFilesExist updates fileExists flag for files.
+func (vr VfsRepo) SetVfsFilesExist(ctx context.Context, fileIDs []int, exists bool) (int, error) {
+	if len(fileIDs) == 0 {
+		return 0, nil
+	}
+
+	res, err := vr.db.ModelContext(ctx, &VfsFile{FileExists: exists}).
+		Column(Columns.VfsFile.FileExists).
+		Where("? IN (?)", pg.Ident(Columns.VfsFile.ID), pg.In(fileIDs)).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// HashForUpdate returns hash locked as in HashesForUpdate, nil is returned if hash is not found or locked by other transaction.
+func (vr VfsRepo) HashForUpdate(ctx context.Context, hash, namespace string) (*VfsHash, error) {
+	h := &VfsHash{}
+	err := vr.db.ModelContext(ctx, h).
+		Where(`? = ?`, pg.Ident(Columns.VfsHash.Hash), hash).
+		Where(`? = ?`, pg.Ident(Columns.VfsHash.Namespace), namespace).
+		For(`NO KEY UPDATE SKIP LOCKED`).
+		Select()
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
 func (vr VfsRepo) HashesForUpdate(ctx context.Context, limit uint64, nsPriority []string) (list []VfsHash, err error) {
 	query := `SELECT "` +
 		strings.Join([]string{
 			Columns.VfsHash.Hash,
 			Columns.VfsHash.Namespace,
 			Columns.VfsHash.Extension,
+			Columns.VfsHash.Attempts,
 		}, `", "`) + `"` +
 		` FROM "` + Tables.VfsHash.Name + `"` +
-		` WHERE "` + Columns.VfsHash.IndexedAt + `" IS NULL`
+		` WHERE "` + Columns.VfsHash.IndexedAt + `" IS NULL` +
+		` AND ("` + Columns.VfsHash.NextAttemptAt + `" IS NULL OR "` + Columns.VfsHash.NextAttemptAt + `" <= now())`
 
 	if len(nsPriority) > 0 {
 		query += ` ORDER BY array_position(?::text[], "` + Columns.VfsHash.Namespace + `"), "` + Columns.VfsHash.FileSize + `"`
@@ -87,7 +195,7 @@ func (vr VfsRepo) HashesForUpdate(ctx context.Context, limit uint64, nsPriority
 	return
 }
 
-// SaveVfsHash checks hash in DB and adds it if hash was not found.
+// SaveVfsHash checks hash in DB and adds it if hash was not found. Indexer is notified about new hash.
 func (vr VfsRepo) SaveVfsHash(ctx context.Context, hash *VfsHash) (err error) {
 	h, err := vr.OneVfsHash(ctx, &VfsHashSearch{
 		Hash:      &hash.Hash,
@@ -103,6 +211,347 @@ func (vr VfsRepo) SaveVfsHash(ctx context.Context, hash *VfsHash) (err error) {
 		return nil
 	}
 
-	_, err = vr.AddVfsHash(ctx, hash)
-	return err
+	if _, err = vr.AddVfsHash(ctx, hash); err != nil {
+		return err
+	}
+
+	return notifyHashes(ctx, vr.db, hash.Namespace)
+}
+
+// failedHashes applies dead-letter filter to query: hash is indexed with error.
+func failedHashes(q *orm.Query) *orm.Query {
+	return q.
+		Where(`? IS NOT NULL`, pg.Ident(Columns.VfsHash.IndexedAt)).
+		Where(`? <> ''`, pg.Ident(Columns.VfsHash.Error))
+}
+
+// FailedHashes returns hashes from namespace that failed indexing after all attempts, recently failed first.
+func (vr VfsRepo) FailedHashes(ctx context.Context, namespace string, pager Pager) (list []VfsHash, err error) {
+	q := vr.db.ModelContext(ctx, &list).
+		Where(`? = ?`, pg.Ident(Columns.VfsHash.Namespace), namespace).
+		OrderExpr(`? DESC`, pg.Ident(Columns.VfsHash.IndexedAt))
+
+	err = pager.Apply(failedHashes(q)).Select()
+	return
+}
+
+// RequeueFailedHashes resets failed hashes from namespace for indexing: all failed hashes if hashes are empty.
+// Indexer is notified about requeued hashes.
+func (vr VfsRepo) RequeueFailedHashes(ctx context.Context, namespace string, hashes []string) (int, error) {
+	q := vr.db.ModelContext(ctx, (*VfsHash)(nil)).
+		Set(`? = NULL`, pg.Ident(Columns.VfsHash.IndexedAt)).
+		Set(`? = ''`, pg.Ident(Columns.VfsHash.Error)).
+		Set(`? = 0`, pg.Ident(Columns.VfsHash.Attempts)).
+		Set(`? = NULL`, pg.Ident(Columns.VfsHash.NextAttemptAt)).
+		Where(`? = ?`, pg.Ident(Columns.VfsHash.Namespace), namespace)
+
+	if len(hashes) > 0 {
+		q.Where(`? IN (?)`, pg.Ident(Columns.VfsHash.Hash), pg.In(hashes))
+	}
+
+	res, err := failedHashes(q).Update()
+	if err != nil {
+		return 0, err
+	}
+
+	if res.RowsAffected() > 0 {
+		err = notifyHashes(ctx, vr.db, namespace)
+	}
+
+	return res.RowsAffected(), err
+}
+
+// ReindexFilter is a filter for hashes reindexing.
+type ReindexFilter struct {
+	Namespace       *string
+	Extension       *string
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	ErroredOnly     bool
+	MissingBlurhash bool
+}
+
+// Apply applies filter to vfsHashes query.
+func (f ReindexFilter) Apply(q *orm.Query) *orm.Query {
+	if f.Namespace != nil {
+		q.Where(`? = ?`, pg.Ident(Columns.VfsHash.Namespace), *f.Namespace)
+	}
+	if f.Extension != nil {
+		q.Where(`? = ?`, pg.Ident(Columns.VfsHash.Extension), *f.Extension)
+	}
+	if f.CreatedFrom != nil {
+		q.Where(`? >= ?`, pg.Ident(Columns.VfsHash.CreatedAt), *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		q.Where(`? < ?`, pg.Ident(Columns.VfsHash.CreatedAt), *f.CreatedTo)
+	}
+	if f.ErroredOnly {
+		q.Where(`? <> ''`, pg.Ident(Columns.VfsHash.Error))
+	}
+	if f.MissingBlurhash {
+		q.Where(`coalesce(?, '') = ''`, pg.Ident(Columns.VfsHash.Blurhash))
+	}
+
+	return q
+}
+
+// ReindexHashes resets indexed hashes matched by filter into indexing queue in batches and returns total queued hashes.
+// Each batch is updated in separate statement, hashes indexed after start are skipped.
+func (vr VfsRepo) ReindexHashes(ctx context.Context, filter ReindexFilter, batchSize int) (total int, err error) {
+	start := time.Now()
+	for {
+		batch := vr.db.ModelContext(ctx, (*VfsHash)(nil)).
+			Column(Columns.VfsHash.Hash, Columns.VfsHash.Namespace).
+			Where(`? < ?`, pg.Ident(Columns.VfsHash.IndexedAt), start).
+			Limit(batchSize).
+			For(`UPDATE SKIP LOCKED`)
+
+		res, err := vr.db.ModelContext(ctx, (*VfsHash)(nil)).
+			Set(`? = NULL`, pg.Ident(Columns.VfsHash.IndexedAt)).
+			Set(`? = ''`, pg.Ident(Columns.VfsHash.Error)).
+			Set(`? = 0`, pg.Ident(Columns.VfsHash.Attempts)).
+			Set(`? = NULL`, pg.Ident(Columns.VfsHash.NextAttemptAt)).
+			Where(`(?, ?) IN (?)`, pg.Ident(Columns.VfsHash.Hash), pg.Ident(Columns.VfsHash.Namespace), filter.Apply(batch)).
+			Update()
+		if err != nil {
+			return total, err
+		}
+
+		total += res.RowsAffected()
+		if res.RowsAffected() < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		err = notifyHashes(ctx, vr.db, "")
+	}
+
+	return total, err
+}
+
+// HashQueueStats is indexing queue stats for namespace.
+type HashQueueStats struct {
+	Namespace string `pg:"namespace"`
+	Pending   int    `pg:"pending"`
+	Retrying  int    `pg:"retrying"`
+	Failed    int    `pg:"failed"`
+}
+
+// HashQueueStats returns pending, retrying and failed hashes count per namespace.
+func (vr VfsRepo) HashQueueStats(ctx context.Context) (list []HashQueueStats, err error) {
+	_, err = vr.db.QueryContext(ctx, &list, `
+SELECT "namespace",
+	count(*) FILTER (WHERE "indexedAt" IS NULL) AS "pending",
+	count(*) FILTER (WHERE "indexedAt" IS NULL AND "error" <> '') AS "retrying",
+	count(*) FILTER (WHERE "indexedAt" IS NOT NULL AND "error" <> '') AS "failed"
+FROM "vfsHashes"
+WHERE "indexedAt" IS NULL OR "error" <> ''
+GROUP BY "namespace"`)
+
+	return
+}
+
+// AddVfsHashes adds new hashes, existing hashes are skipped. Indexer is notified about new hashes.
+func (vr VfsRepo) AddVfsHashes(ctx context.Context, hashes []VfsHash) (int, error) {
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+
+	res, err := vr.db.ModelContext(ctx, &hashes).
+		OnConflict("DO NOTHING").
+		Insert()
+	if err != nil {
+		return 0, err
+	}
+
+	if res.RowsAffected() > 0 {
+		err = notifyHashes(ctx, vr.db, "")
+	}
+
+	return res.RowsAffected(), err
+}
+
+// SimilarVfsHash is a VfsHash with hamming distance of perceptual hash.
+type SimilarVfsHash struct {
+	VfsHash
+
+	Distance int `pg:"distance"`
+}
+
+// SimilarHashes returns hashes from namespace within hamming distance of perceptual hash, excluding hash itself.
+func (vr VfsRepo) SimilarHashes(ctx context.Context, hash, namespace string, phash int64, distance, limit int) (list []SimilarVfsHash, err error) {
+	_, err = vr.db.QueryContext(ctx, &list, `
+SELECT t.*, d."distance" FROM "vfsHashes" t,
+	LATERAL (SELECT length(replace((t."phash" # ?)::bit(64)::text, '0', '')) AS "distance") d
+WHERE t."namespace" = ? AND t."hash" <> ? AND t."phash" IS NOT NULL AND d."distance" <= ?
+ORDER BY d."distance", t."createdAt" DESC
+LIMIT ?`, phash, namespace, hash, distance, limit)
+
+	return
+}
+
+// likeEscaper escapes LIKE pattern special characters.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// FileSearch is a filter for files search across folders, all fields are optional.
+type FileSearch struct {
+	Query        *string // title words or substring
+	RootFolderID *int    // search within folder subtree
+	MimeType     *string // exact mime type or prefix like "image/*"
+	Extension    *string // file extension without dot
+	MinSize      *int
+	MaxSize      *int
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	MinWidth     *int
+	MaxWidth     *int
+	MinHeight    *int
+	MaxHeight    *int
+	Tags         []string // files with all tags
+}
+
+// Apply applies filter to vfsFiles query.
+func (f *FileSearch) Apply(q *orm.Query) *orm.Query {
+	if f == nil {
+		return q
+	}
+
+	title := pg.Ident(Columns.VfsFile.Title)
+	if f.Query != nil && strings.TrimSpace(*f.Query) != "" {
+		// full text match uses to_tsvector index, substring match uses trigram index
+		q.Where(`(to_tsvector('simple', ?) @@ plainto_tsquery('simple', ?) OR ? ILIKE ?)`,
+			title, *f.Query, title, "%"+likeEscaper.Replace(*f.Query)+"%")
+	}
+	if f.RootFolderID != nil {
+		q.Where(`? IN (
+WITH RECURSIVE r AS (
+   SELECT "folderId" FROM "vfsFolders" WHERE "folderId" = ?
+   UNION SELECT ff."folderId" FROM "vfsFolders" ff
+ 		JOIN r ON ff."parentFolderId" = r."folderId"
+   WHERE ff."statusId" <> ?
+)
+SELECT "folderId" FROM r)`, pg.Ident(Columns.VfsFile.FolderID), *f.RootFolderID, StatusDeleted)
+	}
+	if f.MimeType != nil {
+		if prefix, ok := strings.CutSuffix(*f.MimeType, "*"); ok {
+			q.Where(`? LIKE ?`, pg.Ident(Columns.VfsFile.MimeType), likeEscaper.Replace(prefix)+"%")
+		} else {
+			q.Where(`? = ?`, pg.Ident(Columns.VfsFile.MimeType), *f.MimeType)
+		}
+	}
+	if f.Extension != nil {
+		q.Where(`lower(?) LIKE ?`, pg.Ident(Columns.VfsFile.Path), "%."+likeEscaper.Replace(strings.ToLower(*f.Extension)))
+	}
+	if f.MinSize != nil {
+		q.Where(`? >= ?`, pg.Ident(Columns.VfsFile.FileSize), *f.MinSize)
+	}
+	if f.MaxSize != nil {
+		q.Where(`? <= ?`, pg.Ident(Columns.VfsFile.FileSize), *f.MaxSize)
+	}
+	if f.CreatedFrom != nil {
+		q.Where(`? >= ?`, pg.Ident(Columns.VfsFile.CreatedAt), *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		q.Where(`? < ?`, pg.Ident(Columns.VfsFile.CreatedAt), *f.CreatedTo)
+	}
+
+	if len(f.Tags) > 0 {
+		filesWithTags(q, f.Tags)
+	}
+
+	// params are stored as json text
+	for _, p := range []struct {
+		key, op string
+		value   *int
+	}{
+		{"width", ">=", f.MinWidth},
+		{"width", "<=", f.MaxWidth},
+		{"height", ">=", f.MinHeight},
+		{"height", "<=", f.MaxHeight},
+	} {
+		if p.value != nil {
+			q.Where(`coalesce((nullif(?, '')::jsonb->>?)::int, 0) `+p.op+` ?`, pg.Ident(Columns.VfsFile.Params), p.key, *p.value)
+		}
+	}
+
+	return q
+}
+
+// SearchVfsFiles returns files matched by search and total count of matched files.
+func (vr VfsRepo) SearchVfsFiles(ctx context.Context, search *FileSearch, pager Pager, ops ...OpFunc) (list []VfsFile, count int, err error) {
+	q := buildQuery(ctx, vr.db, &list, nil, vr.filters[Tables.VfsFile.Name], pager, ops...)
+	count, err = search.Apply(q).SelectAndCount()
+	return
+}
+
+// FilesWithoutAlt returns ids of files without alt text in any language.
+func (vr VfsRepo) FilesWithoutAlt(ctx context.Context, fileIDs []int64) (ids []int, err error) {
+	err = vr.db.ModelContext(ctx, (*VfsFile)(nil)).
+		Column(Columns.VfsFile.ID).
+		Where(`? IN (?)`, pg.Ident(Columns.VfsFile.ID), pg.Ints(fileIDs)).
+		Where(`NOT EXISTS (SELECT 1 FROM jsonb_each(coalesce(?, '{}'::jsonb)) e WHERE coalesce(e.value->>'alt', '') <> '')`, pg.Ident(Columns.VfsFile.Texts)).
+		Order(Columns.VfsFile.ID).
+		Select(&ids)
+
+	return
+}
+
+// SetFileText sets file text for language by single statement, nil text removes language.
+// If requireAlt is set, published file is updated only if it has alt text in any language after update.
+// Returns false if enabled file is not found or alt text is required.
+func (vr VfsRepo) SetFileText(ctx context.Context, fileID int, lang string, text *VfsFileText, requireAlt bool) (bool, error) {
+	texts := `coalesce("texts", '{}'::jsonb) - ?1::text`
+	var value []byte
+	if text != nil {
+		b, err := json.Marshal(text)
+		if err != nil {
+			return false, err
+		}
+		texts, value = `coalesce("texts", '{}'::jsonb) || jsonb_build_object(?1::text, ?2::jsonb)`, b
+	}
+
+	query := fmt.Sprintf(`UPDATE "%[1]s" SET "texts" = %[2]s
+WHERE "fileId" = ?0 AND "statusId" = ?4
+	AND (NOT ?3 OR NOT "isPublished" OR EXISTS (SELECT 1 FROM jsonb_each(%[2]s) e WHERE coalesce(e.value->>'alt', '') <> ''))`, Tables.VfsFile.Name, texts)
+	res, err := vr.db.ExecContext(ctx, query, fileID, lang, string(value), requireAlt, StatusEnabled)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// SetFilesPublished updates isPublished flag for not deleted files. Returns total updated files.
+func (vr VfsRepo) SetFilesPublished(ctx context.Context, fileIDs []int64, isPublished bool) (int, error) {
+	res, err := vr.db.ModelContext(ctx, &VfsFile{IsPublished: isPublished}).
+		Column(Columns.VfsFile.IsPublished).
+		Where(`? IN (?)`, pg.Ident(Columns.VfsFile.ID), pg.Ints(fileIDs)).
+		Where(`? <> ?`, pg.Ident(Columns.VfsFile.StatusID), StatusDeleted).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// FolderTreeStats returns total not deleted sub folders and files in folder subtree, files of folder itself are included.
+// Subtree folders and files are locked with FOR UPDATE until the end of transaction,
+// so new files and sub folders can't be added to subtree concurrently.
+func (vr VfsRepo) FolderTreeStats(ctx context.Context, folderID int) (folders, files int, err error) {
+	_, err = vr.db.QueryOneContext(ctx, pg.Scan(&folders, &files), `
+WITH RECURSIVE r AS (
+   SELECT "folderId" FROM "vfsFolders" WHERE "folderId" = ?0 AND "statusId" <> ?1
+   UNION SELECT ff."folderId" FROM "vfsFolders" ff
+ 		JOIN r ON ff."parentFolderId" = r."folderId"
+   WHERE ff."statusId" <> ?1
+), lf AS (
+   SELECT "folderId" FROM "vfsFolders" WHERE "folderId" IN (SELECT "folderId" FROM r) FOR UPDATE
+), lff AS (
+   SELECT "fileId" FROM "vfsFiles" WHERE "folderId" IN (SELECT "folderId" FROM r) AND "statusId" <> ?1 FOR UPDATE
+)
+SELECT (SELECT count(*) FROM lf) - 1, (SELECT count(*) FROM lff)`, folderID, StatusDeleted)
+
+	return
 }
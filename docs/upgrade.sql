@@ -0,0 +1,69 @@
+-- Upgrade of existing vfs database to current docs/vfs.sql schema.
+-- Script is idempotent and could be applied several times: psql -f docs/upgrade.sql vfs
+
+Create extension if not exists pg_trgm;
+
+Alter table "vfsFiles" add column if not exists "meta" jsonb;
+Alter table "vfsFiles" add column if not exists "texts" jsonb;
+Alter table "vfsFiles" add column if not exists "isPublished" Boolean NOT NULL Default false;
+Alter table "vfsFiles" add column if not exists "deletedAt" Timestamp;
+
+Alter table "vfsFolders" add column if not exists "deletedAt" Timestamp;
+Alter table "vfsFolders" add column if not exists "quotaBytes" Bigint;
+Alter table "vfsFolders" add column if not exists "quotaFiles" Integer;
+
+Alter table "vfsHashes" add column if not exists "params" jsonb;
+Alter table "vfsHashes" add column if not exists "color" varchar(7);
+Alter table "vfsHashes" add column if not exists "palette" varchar(7)[];
+Alter table "vfsHashes" add column if not exists "phash" bigint;
+Alter table "vfsHashes" add column if not exists "attempts" int not null default 0;
+Alter table "vfsHashes" add column if not exists "nextAttemptAt" Timestamp with time zone;
+
+Create table if not exists "vfsFileVersions"
+(
+    "versionId" Serial NOT NULL,
+    "fileId" Integer NOT NULL,
+    "path" Varchar(255) NOT NULL,
+    "params" Text,
+    "mimeType" Varchar(255) NOT NULL,
+    "fileSize" Integer Default 0,
+    "createdAt" Timestamp NOT NULL Default now(),
+    primary key ("versionId"),
+    foreign key ("fileId") references "vfsFiles" ("fileId") on update restrict on delete cascade
+) Without Oids;
+
+Create table if not exists "vfsTags"
+(
+    "tagId" Serial NOT NULL,
+    "title" varchar(64) NOT NULL,
+    "createdAt" Timestamp with time zone NOT NULL Default now(),
+    primary key ("tagId"),
+    unique ("title")
+) Without Oids;
+
+Create table if not exists "vfsFileTags"
+(
+    "fileId" Integer NOT NULL,
+    "tagId" Integer NOT NULL,
+    primary key ("fileId","tagId"),
+    foreign key ("fileId") references "vfsFiles" ("fileId") on update restrict on delete cascade,
+    foreign key ("tagId") references "vfsTags" ("tagId") on update restrict on delete cascade
+) Without Oids;
+
+Create table if not exists "vfsScans"
+(
+    "namespace" varchar(32) not null,
+    "scannedAt" Timestamp with time zone not null,
+    primary key ("namespace")
+) Without Oids;
+
+Create index if not exists "IX_vfsHashes_namespace_phash" on "vfsHashes" ("namespace") where "phash" is not null;
+Create index if not exists "IX_vfsHashes_namespace_error" on "vfsHashes" ("namespace") where "error" <> '';
+Create index if not exists "IX_vfsHashes_createdAt" on "vfsHashes" ("createdAt");
+Create index if not exists "IX_vfsFiles_title_trgm" on "vfsFiles" using gin ("title" gin_trgm_ops);
+Create index if not exists "IX_vfsFiles_title_fts" on "vfsFiles" using gin (to_tsvector('simple', "title"));
+Create index if not exists "IX_vfsFiles_createdAt" on "vfsFiles" ("createdAt");
+Create index if not exists "IX_FK_vfsFileTagsTagId_vfsFileTags" on "vfsFileTags" ("tagId");
+Create index if not exists "IX_FK_vfsFileVersionsFileId_vfsFileVersions" on "vfsFileVersions" ("fileId");
+Create index if not exists "IX_vfsFiles_deletedAt" on "vfsFiles" ("deletedAt") where "deletedAt" is not null;
+Create index if not exists "IX_vfsFolders_deletedAt" on "vfsFolders" ("deletedAt") where "deletedAt" is not null;
@@ -1,3 +1,5 @@
+Create extension if not exists pg_trgm;
+
 Create table "vfsFiles"
 (
     "fileId" Serial NOT NULL,
@@ -11,6 +13,10 @@ Create table "vfsFiles"
     "fileExists" Boolean NOT NULL Default true,
     "statusId" Integer NOT NULL,
     "createdAt" Timestamp NOT NULL Default now(),
+    "meta" jsonb,
+    "texts" jsonb,
+    "isPublished" Boolean NOT NULL Default false,
+    "deletedAt" Timestamp,
     primary key ("fileId")
 ) Without Oids;
 
@@ -22,6 +28,9 @@ Create table "vfsFolders"
     "isFavorite" Boolean Default false,
     "createdAt" Timestamp NOT NULL Default now(),
     "statusId" Integer NOT NULL,
+    "deletedAt" Timestamp,
+    "quotaBytes" Bigint,
+    "quotaFiles" Integer,
     primary key ("folderId")
 ) Without Oids;
 
@@ -37,12 +46,66 @@ Create table "vfsHashes"
     "error" text,
     "createdAt" Timestamp with time zone NOT NULL Default now(),
     "indexedAt" Timestamp with time zone,
+    "params" jsonb,
+    "color" varchar(7),
+    "palette" varchar(7)[],
+    "phash" bigint,
+    "attempts" int not null default 0,
+    "nextAttemptAt" Timestamp with time zone,
     primary key ("hash","namespace")
 ) Without Oids;
 
+Create table "vfsFileVersions"
+(
+    "versionId" Serial NOT NULL,
+    "fileId" Integer NOT NULL,
+    "path" Varchar(255) NOT NULL,
+    "params" Text,
+    "mimeType" Varchar(255) NOT NULL,
+    "fileSize" Integer Default 0,
+    "createdAt" Timestamp NOT NULL Default now(),
+    primary key ("versionId")
+) Without Oids;
+
+Create table "vfsTags"
+(
+    "tagId" Serial NOT NULL,
+    "title" varchar(64) NOT NULL,
+    "createdAt" Timestamp with time zone NOT NULL Default now(),
+    primary key ("tagId"),
+    unique ("title")
+) Without Oids;
+
+Create table "vfsFileTags"
+(
+    "fileId" Integer NOT NULL,
+    "tagId" Integer NOT NULL,
+    primary key ("fileId","tagId")
+) Without Oids;
+
+Create table "vfsScans"
+(
+    "namespace" varchar(32) not null,
+    "scannedAt" Timestamp with time zone not null,
+    primary key ("namespace")
+) Without Oids;
+
 
 Create index "IX_FK_vfsFoldersFolderId_vfsFolders" on "vfsFolders" ("parentFolderId");
 Alter table "vfsFolders" add  foreign key ("parentFolderId") references "vfsFolders" ("folderId") on update restrict on delete restrict;
 Create index "IX_FK_vfsFilesFolderId_vfsFiles" on "vfsFiles" ("folderId");
 Alter table "vfsFiles" add  foreign key ("folderId") references "vfsFolders" ("folderId") on update restrict on delete restrict;
 Create index "IX_vfsHashes_indexedAt" on "vfsHashes" ("indexedAt");
+Create index "IX_vfsHashes_namespace_phash" on "vfsHashes" ("namespace") where "phash" is not null;
+Create index "IX_vfsHashes_namespace_error" on "vfsHashes" ("namespace") where "error" <> '';
+Create index "IX_vfsHashes_createdAt" on "vfsHashes" ("createdAt");
+Create index "IX_vfsFiles_title_trgm" on "vfsFiles" using gin ("title" gin_trgm_ops);
+Create index "IX_vfsFiles_title_fts" on "vfsFiles" using gin (to_tsvector('simple', "title"));
+Create index "IX_vfsFiles_createdAt" on "vfsFiles" ("createdAt");
+Create index "IX_FK_vfsFileTagsTagId_vfsFileTags" on "vfsFileTags" ("tagId");
+Alter table "vfsFileTags" add foreign key ("fileId") references "vfsFiles" ("fileId") on update restrict on delete cascade;
+Alter table "vfsFileTags" add foreign key ("tagId") references "vfsTags" ("tagId") on update restrict on delete cascade;
+Create index "IX_FK_vfsFileVersionsFileId_vfsFileVersions" on "vfsFileVersions" ("fileId");
+Alter table "vfsFileVersions" add foreign key ("fileId") references "vfsFiles" ("fileId") on update restrict on delete cascade;
+Create index "IX_vfsFiles_deletedAt" on "vfsFiles" ("deletedAt") where "deletedAt" is not null;
+Create index "IX_vfsFolders_deletedAt" on "vfsFolders" ("deletedAt") where "deletedAt" is not null;
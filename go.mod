@@ -5,6 +5,7 @@ go 1.24.5
 require (
 	github.com/BurntSushi/toml v1.6.0
 	github.com/bbrks/go-blurhash v1.2.0
+	github.com/fsnotify/fsnotify v1.10.1
 	github.com/gabriel-vasile/mimetype v1.4.13
 	github.com/go-pg/pg/v10 v10.15.0
 	github.com/go-pg/urlstruct v1.0.1
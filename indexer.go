@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,12 +32,36 @@ import (
 )
 
 const (
-	defaultInterval  = time.Second * 5
-	defaultCacheSize = 1024
-	httpTimeLayout   = `Mon, 02 Jan 2006 15:04:05 MST`
-	DefaultNamespace = "default"
+	defaultInterval    = time.Second * 5
+	minInterval        = time.Millisecond * 100 // delay after full batch, prevents busy loop on hashes failing again
+	defaultIdleBackoff = time.Minute
+	scanTimeSkew       = time.Minute
+	defaultMaxAttempts = 5
+	defaultRetryDelay  = time.Minute
+	maxRetryDelay      = time.Hour * 24
+	defaultCacheSize   = 1024
+	defaultExecTimeout = time.Minute
+	httpTimeLayout     = `Mon, 02 Jan 2006 15:04:05 MST`
+	DefaultNamespace   = "default"
 )
 
+// indexedColumns are vfsHashes columns updated by indexer.
+var indexedColumns = []string{
+	db.Columns.VfsHash.Hash,
+	db.Columns.VfsHash.Namespace,
+	db.Columns.VfsHash.Height,
+	db.Columns.VfsHash.Width,
+	db.Columns.VfsHash.IndexedAt,
+	db.Columns.VfsHash.Blurhash,
+	db.Columns.VfsHash.Error,
+	db.Columns.VfsHash.Params,
+	db.Columns.VfsHash.Color,
+	db.Columns.VfsHash.Palette,
+	db.Columns.VfsHash.Phash,
+	db.Columns.VfsHash.Attempts,
+	db.Columns.VfsHash.NextAttemptAt,
+}
+
 type HashIndexer struct {
 	embedlog.Logger
 	dbc          db.DB
@@ -46,9 +71,20 @@ type HashIndexer struct {
 	batchSize    uint64
 	calcBlurHash bool
 	nsPriority   []string
+	indexers     []indexerEntry
+
+	// idleBackoff is max polling interval for empty queue.
+	idleBackoff time.Duration
+
+	// maxAttempts is max indexing attempts for transient errors, retryDelay is initial delay between attempts.
+	maxAttempts int
+	retryDelay  time.Duration
 
 	cache    *lru.ARCCache
-	t        *time.Ticker
+	stats    *indexerStats
+	scanJobs *scanJobs
+	stop     chan struct{}
+	stopOnce *sync.Once
 	scanning *atomic.Bool
 	indexing *atomic.Bool
 }
@@ -60,22 +96,107 @@ type HashInfo struct {
 	Height    int
 	FileSize  int64
 	BlurHash  string
+	Color     string   // dominant color, e.g. "#ff0000"
+	Palette   []string // most used colors
+	PHash     *int64   // perceptual hash (dHash)
+
+	// Params is additional info filled by indexers, saved into vfsHashes.params.
+	Params db.VfsHashParams
+}
+
+// params returns additional indexed info for vfsHashes.params or nil.
+func (h HashInfo) params() *db.VfsHashParams {
+	p := h.Params
+	if p.Duration == 0 && p.Codec == "" && p.Poster == "" && p.Pages == 0 && p.Thumbnail == "" && len(p.Extra) == 0 {
+		return nil
+	}
+
+	return &p
 }
 
 type ScanResults struct {
 	Scanned  uint64        `json:"scanned"`
 	Added    uint64        `json:"added"`
 	Duration time.Duration `json:"duration"`
+
+	// files scan results
+	Missing  uint64 `json:"missing,omitempty"`  // files marked as not existing
+	Restored uint64 `json:"restored,omitempty"` // files marked as existing again
+	Imported uint64 `json:"imported,omitempty"` // unknown files added into unsorted folder
 }
 
 type cacheEntry struct {
-	data  []byte
-	mtime time.Time
+	data        []byte
+	mtime       time.Time
+	contentType string
+}
+
+// HashIndexerOption is a function that sets optional HashIndexer params.
+type HashIndexerOption func(hi *HashIndexer)
+
+// WithIndexer registers Indexer for given mime types, e.g. "image/png", "image/*" or "*" for any file.
+// Indexers are called in registration order.
+func WithIndexer(idx Indexer, mimeTypes ...string) HashIndexerOption {
+	return func(hi *HashIndexer) {
+		for _, mt := range mimeTypes {
+			hi.indexers = append(hi.indexers, indexerEntry{mimeType: mt, indexer: idx})
+		}
+	}
+}
+
+// WithVideo enables video indexing using ffprobe. Poster frames are generated if ffmpegPath is set.
+func WithVideo(ffprobePath, ffmpegPath string) HashIndexerOption {
+	return func(hi *HashIndexer) {
+		if ffprobePath != "" {
+			WithIndexer(videoIndexer{ffprobePath: ffprobePath, ffmpegPath: ffmpegPath, calcBlurHash: hi.calcBlurHash}, "video/*")(hi)
+		}
+	}
+}
+
+// WithAudio enables audio indexing using ffprobe.
+func WithAudio(ffprobePath string) HashIndexerOption {
+	return func(hi *HashIndexer) {
+		if ffprobePath != "" {
+			WithIndexer(audioIndexer{ffprobePath: ffprobePath}, "audio/*")(hi)
+		}
+	}
+}
+
+// WithPDF enables pdf indexing: first page is rendered via pdftoppm, total pages are detected via pdfinfo if set.
+func WithPDF(pdftoppmPath, pdfinfoPath string) HashIndexerOption {
+	return func(hi *HashIndexer) {
+		if pdftoppmPath != "" {
+			WithIndexer(pdfIndexer{pdftoppmPath: pdftoppmPath, pdfinfoPath: pdfinfoPath, calcBlurHash: hi.calcBlurHash}, "application/pdf")(hi)
+		}
+	}
+}
+
+// WithIdleBackoff sets max polling interval: while queue is empty polling interval is doubled up to maxInterval.
+// New hashes wake up indexer immediately via postgres notifications.
+func WithIdleBackoff(maxInterval time.Duration) HashIndexerOption {
+	return func(hi *HashIndexer) {
+		if maxInterval > 0 {
+			hi.idleBackoff = maxInterval
+		}
+	}
 }
 
-func NewHashIndexer(sl embedlog.Logger, dbc db.DB, repo *db.VfsRepo, vfs VFS, totalWorkers int, batchSize uint64, calculateBlurHash bool, nsPriority []string) *HashIndexer {
+// WithRetry sets max indexing attempts and initial retry delay for transient errors, e.g. file not found or I/O error.
+// Delay is doubled after each attempt. After maxAttempts hash is marked as failed with error.
+func WithRetry(maxAttempts int, delay time.Duration) HashIndexerOption {
+	return func(hi *HashIndexer) {
+		if maxAttempts > 0 {
+			hi.maxAttempts = maxAttempts
+		}
+		if delay > 0 {
+			hi.retryDelay = delay
+		}
+	}
+}
+
+func NewHashIndexer(sl embedlog.Logger, dbc db.DB, repo *db.VfsRepo, vfs VFS, totalWorkers int, batchSize uint64, calculateBlurHash bool, nsPriority []string, opts ...HashIndexerOption) *HashIndexer {
 	cache, _ := lru.NewARC(defaultCacheSize)
-	return &HashIndexer{
+	hi := &HashIndexer{
 		Logger:       sl,
 		dbc:          dbc,
 		repo:         repo,
@@ -87,33 +208,127 @@ func NewHashIndexer(sl embedlog.Logger, dbc db.DB, repo *db.VfsRepo, vfs VFS, to
 		batchSize:    batchSize,
 		calcBlurHash: calculateBlurHash,
 		nsPriority:   nsPriority,
+		idleBackoff:  defaultIdleBackoff,
+		maxAttempts:  defaultMaxAttempts,
+		retryDelay:   defaultRetryDelay,
+		stats:        &indexerStats{},
+		scanJobs:     &scanJobs{},
+		stop:         make(chan struct{}),
+		stopOnce:     &sync.Once{},
 	}
+
+	// images are always indexed
+	WithIndexer(imageIndexer{calcBlurHash: calculateBlurHash}, "image/*")(hi)
+	for _, opt := range opts {
+		opt(hi)
+	}
+
+	return hi
 }
 
+// Start runs indexer until Stop is called. Queue is processed on notifications about new hashes
+// or by polling with defaultInterval, which is increased up to idleBackoff while queue is empty.
 func (hi HashIndexer) Start() {
-	hi.t = time.NewTicker(defaultInterval)
-	for range hi.t.C {
-		wg := sync.WaitGroup{}
-		for i := 0; i < hi.totalWorkers; i++ {
-			wg.Add(1)
-			go func() {
-				start := time.Now()
-				ctx := context.Background()
-				rows, err := hi.ProcessQueue(ctx)
+	ctx := context.Background()
+
+	// listen for new hashes
+	var notifications <-chan pg.Notification
+	if hi.dbc.DB != nil {
+		ln := hi.dbc.Listen(ctx, db.HashesChannel)
+		defer func(ln *pg.Listener) {
+			_ = ln.Close()
+		}(ln)
+		notifications = ln.Channel()
+	}
+
+	interval := defaultInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-hi.stop:
+			return
+		case <-notifications:
+			drainNotifications(notifications)
+		case <-timer.C:
+		}
+
+		rows, full := hi.processWorkers(ctx)
+		interval = nextPollInterval(interval, hi.idleBackoff, rows, full)
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(interval)
+	}
+}
+
+// processWorkers runs ProcessQueue in all workers and returns total processed rows.
+// full is true if any worker got full batch, so queue could have more hashes.
+func (hi HashIndexer) processWorkers(ctx context.Context) (rows int, full bool) {
+	var (
+		total     = atomic.NewInt64(0)
+		fullBatch = atomic.NewBool(false)
+		wg        = sync.WaitGroup{}
+	)
+
+	for i := 0; i < hi.totalWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			rows, err := hi.ProcessQueue(ctx)
+			hi.stats.addRows(rows, time.Now())
+			total.Add(int64(rows))
+			if rows > 0 && uint64(rows) >= hi.batchSize {
+				fullBatch.Store(true)
+			}
+			if err != nil || rows > 0 {
 				hi.PrintOrErr(ctx, "process queue", err, "rows", rows, "duration", time.Since(start).String())
-				wg.Done()
-			}()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(total.Load()), fullBatch.Load()
+}
+
+// nextPollInterval returns next queue polling interval:
+// min for full batch, default for processed rows and doubled interval up to maxIdle for empty queue.
+func nextPollInterval(current, maxIdle time.Duration, rows int, full bool) time.Duration {
+	switch {
+	case full:
+		return minInterval
+	case rows > 0, current < defaultInterval:
+		return defaultInterval
+	}
+
+	return max(min(current*2, maxIdle), defaultInterval)
+}
+
+// drainNotifications skips pending notifications, one queue processing is enough for all of them.
+func drainNotifications(ch <-chan pg.Notification) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
 		}
-		wg.Wait()
 	}
 }
 
 func (hi HashIndexer) Stop() {
-	if hi.t != nil {
-		hi.t.Stop()
-	}
+	hi.stopOnce.Do(func() {
+		close(hi.stop)
+		hi.scanJobs.cancelAll()
+	})
 }
 
+// IndexFile detects file mime type and runs all registered indexers for it.
 func (hi HashIndexer) IndexFile(ns, relFilepath string) (HashInfo, error) {
 	var hash HashInfo
 
@@ -132,47 +347,87 @@ func (hi HashIndexer) IndexFile(ns, relFilepath string) (HashInfo, error) {
 	// get file size
 	hash.FileSize = f.Size()
 
-	// detect image size
-	im, _, err := image.DecodeConfig(reader)
+	// detect mime type
+	mType, err := mimeType(reader)
 	if err != nil {
 		return hash, err
 	}
 
-	hash.Width = im.Width
-	hash.Height = im.Height
-
-	// detect image hash
-	_, err = reader.Seek(0, io.SeekStart)
-	if err != nil {
-		return hash, err
+	file := IndexedFile{
+		Namespace: ns,
+		RelPath:   relFilepath,
+		Path:      hi.vfs.Path(ns, relFilepath),
+		MimeType:  mType,
+		File:      reader,
+		vfs:       hi.vfs,
 	}
 
-	img, _, err := image.Decode(reader)
-	if err != nil {
-		return hash, err
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultExecTimeout)
+	defer cancel()
 
-	// calculate blurhash
-	if hi.calcBlurHash {
-		bh, err := blurhash.Encode(4, 3, img)
-		if err != nil {
+	indexed := false
+	for _, e := range hi.indexers {
+		if !e.match(mType) {
+			continue
+		}
+
+		// rewind file for every indexer
+		if _, err = reader.Seek(0, io.SeekStart); err != nil {
 			return hash, err
 		}
-		hash.BlurHash = bh
+
+		if err = e.indexer.Index(ctx, file, &hash); err != nil {
+			return hash, err
+		}
+		indexed = true
+	}
+
+	if !indexed {
+		return hash, fmt.Errorf("%w: %s", ErrUnsupportedMimeType, mType)
 	}
 
-	return hash, err
+	return hash, nil
 }
 
 // ScanFiles reads media folder, detects namespaces & files and loads files into vfsHashes.
-func (hi HashIndexer) ScanFiles(ctx context.Context) (r ScanResults, err error) {
+func (hi HashIndexer) ScanFiles(ctx context.Context, opts ScanOptions) (r ScanResults, err error) {
 	// forbid running FS scan in parallel
-	if hi.scanning.Load() {
-		return r, errors.New("already scanning")
+	if !hi.scanning.CompareAndSwap(false, true) {
+		return r, ErrScanRunning
 	}
-	hi.scanning.Store(true)
 	defer hi.scanning.Store(false)
 
+	return hi.scanFiles(ctx, opts, &scanProgress{})
+}
+
+// scanFiles scans namespaces folders and updates scan progress.
+// Start time is saved for each namespace and used as modification time threshold for next incremental scan.
+func (hi HashIndexer) scanFiles(ctx context.Context, opts ScanOptions, p *scanProgress) (r ScanResults, err error) {
+	if opts.Files {
+		return hi.scanVfsFiles(ctx, opts, p)
+	}
+
+	start := time.Now()
+	namespaces, err := hi.scanNamespaces(opts.Namespaces)
+	if err != nil {
+		return r, err
+	}
+
+	// get last scan time for incremental scan
+	since := make(map[string]*time.Time, len(namespaces))
+	if opts.Incremental {
+		for _, ns := range namespaces {
+			t, err := hi.dbc.LastScanTime(ctx, hashNamespace(ns))
+			if err != nil {
+				return r, err
+			}
+			if t != nil {
+				st := t.Add(-scanTimeSkew)
+				since[ns] = &st
+			}
+		}
+	}
+
 	// pipe for CSV -> temp table
 	pr, pw := io.Pipe()
 	cw := csv.NewWriter(pw)
@@ -201,37 +456,105 @@ func (hi HashIndexer) ScanFiles(ctx context.Context) (r ScanResults, err error)
 			}
 			r.Added = uint64(updated)
 			r.Duration = duration
-			return nil
+
+			hashNamespaces := make([]string, 0, len(namespaces))
+			for _, ns := range namespaces {
+				hashNamespaces = append(hashNamespaces, hashNamespace(ns))
+			}
+			return hi.dbc.SaveScanTime(ctx, tx, hashNamespaces, start)
 		})
 	}()
 
 	// scan files
-	separator := string(filepath.Separator)
-	rootDir := strings.TrimSuffix(hi.vfs.cfg.Path, separator) + separator
-	if err := filepath.Walk(hi.vfs.cfg.Path, hi.walkFn(rootDir, cw)); err != nil {
-		cw.Flush()
-		_ = pw.Close()
-		return r, err
+	for _, ns := range namespaces {
+		if wErr := hi.walkNamespace(ctx, ns, since[ns], cw, p); wErr != nil {
+			// rollback db transaction
+			_ = pw.CloseWithError(wErr)
+			wg.Wait()
+			return r, wErr
+		}
 	}
 	cw.Flush()
-	_ = pw.Close()
+	_ = pw.CloseWithError(cw.Error())
 	wg.Wait()
 
+	if err == nil {
+		hi.stats.setLastScan(r, time.Now())
+	}
+
 	return r, err
 }
 
+// scanNamespaces returns vfs namespaces for scan: all namespaces for empty list.
+// DefaultNamespace or empty string is used for public namespace.
+func (hi HashIndexer) scanNamespaces(list []string) ([]string, error) {
+	if len(list) == 0 {
+		return append([]string{NamespacePublic}, hi.vfs.cfg.Namespaces...), nil
+	}
+
+	namespaces := make([]string, 0, len(list))
+	for _, ns := range list {
+		if ns == DefaultNamespace {
+			ns = NamespacePublic
+		}
+		if !hi.vfs.IsValidNamespace(ns) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNamespace, ns)
+		}
+		if !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	return namespaces, nil
+}
+
+// walkNamespace walks namespace folder. Hash directories not modified since are skipped if since is set.
+func (hi HashIndexer) walkNamespace(ctx context.Context, ns string, since *time.Time, cw *csv.Writer, p *scanProgress) error {
+	root := hi.vfs.Path(ns, "")
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return filepath.WalkDir(root, hi.walkFn(ctx, ns, since, cw, p))
+}
+
 type ScanFilesResponse struct {
-	ScanResults `json:",omitempty"`
-	Error       string `json:"error,omitempty"` // error message
+	*ScanJob `json:",omitempty"`
+	Error    string `json:"error,omitempty"` // error message
 }
 
+// ScanFilesHandler starts background files scan and returns scan job, see ScanJob.
+// Query params: ns is a namespace for scan (could be repeated), incremental=true enables incremental scan,
+// files=true scans vfsFiles instead of hashes, folderId is a folder for unknown files imported by files scan.
 func (hi HashIndexer) ScanFilesHandler(c echo.Context) error {
-	sr, err := hi.ScanFiles(context.Background())
-	if err != nil {
+	opts := ScanOptions{Namespaces: c.QueryParams()["ns"]}
+	for param, v := range map[string]*bool{"incremental": &opts.Incremental, "files": &opts.Files} {
+		if s := c.QueryParam(param); s != "" {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, ScanFilesResponse{Error: err.Error()})
+			}
+			*v = b
+		}
+	}
+	if s := c.QueryParam("folderId"); s != "" {
+		folderID, err := strconv.Atoi(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ScanFilesResponse{Error: err.Error()})
+		}
+		opts.UnsortedFolderID = &folderID
+	}
+
+	job, err := hi.StartScan(opts)
+	if errors.Is(err, ErrScanRunning) {
+		return c.JSON(http.StatusConflict, ScanFilesResponse{Error: err.Error()})
+	} else if errors.Is(err, ErrInvalidNamespace) || errors.Is(err, ErrFolderNotFound) {
+		return c.JSON(http.StatusBadRequest, ScanFilesResponse{Error: err.Error()})
+	} else if err != nil {
 		return c.JSON(http.StatusInternalServerError, ScanFilesResponse{Error: err.Error()})
 	}
 
-	return c.JSON(http.StatusOK, ScanFilesResponse{ScanResults: sr})
+	return c.JSON(http.StatusAccepted, ScanFilesResponse{ScanJob: &job})
 }
 
 // ProcessQueue gets not indexed data from vfsHashes, index and saves data to db.
@@ -250,38 +573,14 @@ func (hi HashIndexer) ProcessQueue(ctx context.Context) (int, error) {
 
 		// index file
 		now := time.Now().UTC()
-		for i, v := range list {
-			ns := v.Namespace
-			if ns == DefaultNamespace {
-				ns = ""
-			}
-			list[i].IndexedAt = &now
-			info, err := hi.IndexFile(ns, NewFileHash(v.Hash, v.Extension).File())
-			if err != nil {
-				if errors.Is(err, os.ErrNotExist) {
-					// skip in case file not found (not downloaded yet)
-					list[i].IndexedAt = nil
-				}
-				list[i].Error = err.Error()
-				continue
-			}
-			list[i].Height = info.Height
-			list[i].Width = info.Width
-			list[i].Blurhash = &info.BlurHash
+		for i := range list {
+			hi.indexHash(&list[i], now)
 		}
 
 		// save data to db
 		_, err = tx.
 			ModelContext(ctx, &list).
-			Column(
-				db.Columns.VfsHash.Hash,
-				db.Columns.VfsHash.Namespace,
-				db.Columns.VfsHash.Height,
-				db.Columns.VfsHash.Width,
-				db.Columns.VfsHash.IndexedAt,
-				db.Columns.VfsHash.Blurhash,
-				db.Columns.VfsHash.Error,
-			).
+			Column(indexedColumns...).
 			Update()
 
 		rows = len(list)
@@ -290,6 +589,82 @@ func (hi HashIndexer) ProcessQueue(ctx context.Context) (int, error) {
 	return rows, err
 }
 
+// indexHash indexes hash file and fills indexed fields of vfsHash.
+// Transient errors are retried with exponential backoff, permanent errors and last attempt mark hash as failed.
+func (hi HashIndexer) indexHash(h *db.VfsHash, now time.Time) {
+	ns := h.Namespace
+	if ns == DefaultNamespace {
+		ns = ""
+	}
+
+	h.IndexedAt = &now
+	h.NextAttemptAt = nil
+	info, err := hi.IndexFile(ns, NewFileHash(h.Hash, h.Extension).File())
+	if err != nil {
+		h.Attempts++
+		h.Error = err.Error()
+		if !isPermanentIndexError(err) && h.Attempts < hi.maxAttempts {
+			// retry later, e.g. file is not downloaded yet
+			next := now.Add(retryDelay(hi.retryDelay, h.Attempts))
+			h.IndexedAt, h.NextAttemptAt = nil, &next
+		}
+		return
+	}
+
+	h.Error = ""
+	h.Height = info.Height
+	h.Width = info.Width
+	h.Blurhash = &info.BlurHash
+	h.Params = info.params()
+	h.Palette = info.Palette
+	h.Phash = info.PHash
+	if info.Color != "" {
+		h.Color = &info.Color
+	}
+}
+
+// isPermanentIndexError checks that file could not be indexed on retry.
+func isPermanentIndexError(err error) bool {
+	return errors.Is(err, ErrUnsupportedMimeType) ||
+		errors.Is(err, image.ErrFormat) ||
+		errors.Is(err, errNoMediaStream) ||
+		errors.Is(err, errNoPages)
+}
+
+// retryDelay returns exponential delay before next indexing attempt, up to maxRetryDelay.
+func retryDelay(delay time.Duration, attempts int) time.Duration {
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
+
+// IndexUploaded indexes uploaded hash file synchronously and saves result into vfsHashes if repo is set.
+// Hash row is locked as by queue workers: hash locked by worker is left for it and already indexed hash is returned as is.
+func (hi HashIndexer) IndexUploaded(ctx context.Context, repo *db.VfsRepo, h *db.VfsHash) error {
+	if repo == nil {
+		hi.indexHash(h, time.Now().UTC())
+		return nil
+	}
+
+	return repo.RunInTransaction(ctx, func(repo db.VfsRepo) error {
+		dbh, err := repo.HashForUpdate(ctx, h.Hash, h.Namespace)
+		if err != nil || dbh == nil {
+			return err
+		}
+
+		*h = *dbh
+		if h.IndexedAt != nil {
+			return nil
+		}
+
+		hi.indexHash(h, time.Now().UTC())
+		_, err = repo.UpdateVfsHash(ctx, h, db.WithColumns(indexedColumns...))
+		return err
+	})
+}
+
 func (hi HashIndexer) Preview(c echo.Context) error {
 	nsp, file := c.Param("ns"), c.Param("file")
 	file = strings.TrimSuffix(file, filepath.Ext(file))
@@ -314,7 +689,9 @@ func (hi HashIndexer) Preview(c echo.Context) error {
 		return c.String(http.StatusNotFound, "hash not found")
 	}
 
-	if hash.Width == 0 || hash.Height == 0 || hash.Blurhash == nil || *hash.Blurhash == "" {
+	// reindexed hashes keep prior index fields until indexed again
+	hasThumbnail := hash.IndexedAt != nil && hash.Params != nil && hash.Params.Thumbnail != ""
+	if hash.IndexedAt == nil || !hasThumbnail && (hash.Width == 0 || hash.Height == 0 || hash.Blurhash == nil || *hash.Blurhash == "") {
 		return c.String(http.StatusNotFound, "hash not indexed yet")
 	}
 
@@ -324,6 +701,20 @@ func (hi HashIndexer) Preview(c echo.Context) error {
 		}
 	}
 
+	// use rendered thumbnail for documents
+	if hasThumbnail {
+		if ns == DefaultNamespace {
+			ns = ""
+		}
+		data, err := os.ReadFile(hi.vfs.Path(ns, hash.Params.Thumbnail))
+		if err != nil {
+			return c.String(http.StatusInternalServerError, err.Error())
+		}
+		entry = cacheEntry{data: data, mtime: hash.IndexedAt.UTC(), contentType: "image/jpeg"}
+		hi.cache.Add(key, entry)
+		return writePreview(entry.(cacheEntry), c)
+	}
+
 	newWidth := 32
 	newHeight := int(math.Round(float64(newWidth*hash.Height) / float64(hash.Width)))
 
@@ -335,7 +726,7 @@ func (hi HashIndexer) Preview(c echo.Context) error {
 	if err := png.Encode(buf, img); err != nil {
 		return c.String(http.StatusInternalServerError, err.Error())
 	}
-	entry = cacheEntry{data: buf.Bytes(), mtime: hash.IndexedAt.UTC()}
+	entry = cacheEntry{data: buf.Bytes(), mtime: hash.IndexedAt.UTC(), contentType: "image/png"}
 	hi.cache.Add(key, entry)
 	return writePreview(entry.(cacheEntry), c)
 }
@@ -345,27 +736,49 @@ func writePreview(e cacheEntry, c echo.Context) error {
 	c.Response().Header().Set("Content-Length", strconv.Itoa(len(e.data)))
 	c.Response().Header().Set("Cache-Control", "public, max-age=31536000;")
 
-	return c.Blob(http.StatusOK, "image/png", e.data)
+	return c.Blob(http.StatusOK, e.contentType, e.data)
 }
 
 func cacheKey(ns, hash string) string {
 	return ns + "|" + hash
 }
 
-func (hi HashIndexer) walkFn(rootDir string, cw *csv.Writer) filepath.WalkFunc {
-	return func(path string, info fs.FileInfo, err error) error {
+func (hi HashIndexer) walkFn(ctx context.Context, ns string, since *time.Time, cw *csv.Writer, p *scanProgress) fs.WalkDirFunc {
+	nsRoot := hi.vfs.Path(ns, "")
+	return func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() || !info.Mode().IsRegular() {
+		relPath, err := filepath.Rel(hi.vfs.cfg.Path, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			p.dir.Store(relPath)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			nsPath, err := filepath.Rel(nsRoot, path)
+			if err != nil {
+				return err
+			}
+			return hi.walkDir(ns, nsPath, since, d, p)
+		}
+		if !d.Type().IsRegular() {
 			return nil
 		}
-		relPath := strings.TrimPrefix(path, rootDir)
-		ns := getNs(hi.vfs.cfg.Namespaces, relPath)
+		p.walked.Inc()
 		if !isHashFile(ns, relPath) {
 			return nil
 		}
 
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
 		ext := filepath.Ext(relPath)
 		baseName := strings.TrimSuffix(filepath.Base(relPath), ext)
 		if len(baseName) > 40 {
@@ -374,7 +787,7 @@ func (hi HashIndexer) walkFn(rootDir string, cw *csv.Writer) filepath.WalkFunc {
 
 		if err := cw.Write([]string{
 			baseName,
-			ns,
+			hashNamespace(ns),
 			strconv.FormatInt(info.Size(), 10),
 			strings.TrimPrefix(ext, "."),
 		}); err != nil {
@@ -384,15 +797,55 @@ func (hi HashIndexer) walkFn(rootDir string, cw *csv.Writer) filepath.WalkFunc {
 	}
 }
 
+// walkDir skips other namespaces folders for public namespace and not modified hash directories for incremental scan.
+func (hi HashIndexer) walkDir(ns, nsPath string, since *time.Time, d fs.DirEntry, p *scanProgress) error {
+	if ns == NamespacePublic && nsPath != "." && !strings.ContainsRune(nsPath, filepath.Separator) && hi.vfs.IsValidNamespace(nsPath) {
+		return filepath.SkipDir
+	}
+
+	if since == nil || !isHashDir(nsPath) {
+		return nil
+	}
+
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	// new files are moved into hash directory, so its modification time is changed
+	if info.ModTime().Before(*since) {
+		p.skipped.Inc()
+		return filepath.SkipDir
+	}
+
+	return nil
+}
+
+// getNs returns namespace by first element of path relative to vfs path or empty string for public namespace.
 func getNs(namespaces []string, path string) string {
+	first, _, _ := strings.Cut(path, string(filepath.Separator))
 	for _, ns := range namespaces {
-		if ns != "" && strings.HasPrefix(path, ns) {
+		if ns != "" && ns == first {
 			return ns
 		}
 	}
 	return ""
 }
 
+// isHashDir checks that path is a hash files directory relative to namespace, e.g. "7/0c".
+func isHashDir(path string) bool {
+	if len(path) != 4 || path[1] != filepath.Separator {
+		return false
+	}
+
+	for _, c := range path[0:1] + path[2:4] {
+		if !isHex(c) {
+			return false
+		}
+	}
+	return true
+}
+
 // isHashFile checks if file path has a namespace format.
 // e.g. "7/0c/70c565ef460af43688b7ee6251028db9.jpg"
 func isHashFile(ns string, path string) bool {
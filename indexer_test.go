@@ -1,7 +1,20 @@
 package vfs
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+
+	"github.com/vmkteam/embedlog"
 )
 
 func Test_isHashFile(t *testing.T) {
@@ -73,3 +86,201 @@ func Test_isHashFile(t *testing.T) {
 		})
 	}
 }
+
+func Test_nextPollInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		current time.Duration
+		maxIdle time.Duration
+		rows    int
+		full    bool
+		want    time.Duration
+	}{
+		{name: "full batch", current: defaultInterval, maxIdle: time.Minute, rows: 64, full: true, want: minInterval},
+		{name: "processed after full batch", current: 0, maxIdle: time.Minute, rows: 10, want: defaultInterval},
+		{name: "empty after full batch", current: 0, maxIdle: time.Minute, want: defaultInterval},
+		{name: "processed after idle", current: time.Minute, maxIdle: time.Minute, rows: 1, want: defaultInterval},
+		{name: "idle", current: defaultInterval, maxIdle: time.Minute, want: 2 * defaultInterval},
+		{name: "idle max", current: 40 * time.Second, maxIdle: time.Minute, want: time.Minute},
+		{name: "idle max less than default", current: defaultInterval, maxIdle: time.Second, want: defaultInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPollInterval(tt.current, tt.maxIdle, tt.rows, tt.full); got != tt.want {
+				t.Errorf("nextPollInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_retryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 4, want: 8 * time.Minute},
+		{attempts: 100, want: maxRetryDelay},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			if got := retryDelay(time.Minute, tt.attempts); got != tt.want {
+				t.Errorf("retryDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashIndexer_indexHash(t *testing.T) {
+	dir := t.TempDir()
+	v, err := New(Config{Path: dir}, embedlog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unsupported file
+	txt := NewFileHash("80c565ef460af43688b7ee6251028db9", "txt")
+	if err = os.MkdirAll(filepath.Join(dir, txt.Dir()), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, txt.File()), []byte("plain text"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	hi := NewHashIndexer(embedlog.Logger{}, db.DB{}, nil, v, 1, 1, false, nil, WithRetry(3, time.Minute))
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name     string
+		hash     db.VfsHash
+		attempts int
+		retry    bool
+	}{
+		{name: "not found", hash: db.VfsHash{Hash: "70c565ef460af43688b7ee6251028db9", Namespace: DefaultNamespace}, attempts: 1, retry: true},
+		{name: "not found last attempt", hash: db.VfsHash{Hash: "70c565ef460af43688b7ee6251028db9", Namespace: DefaultNamespace, Attempts: 2}, attempts: 3},
+		{name: "unsupported", hash: db.VfsHash{Hash: txt.Hash, Namespace: DefaultNamespace, Extension: txt.Ext}, attempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.hash
+			hi.indexHash(&h, now)
+
+			if h.Error == "" || h.Attempts != tt.attempts {
+				t.Errorf("indexHash() error = %q, attempts = %v, want %v", h.Error, h.Attempts, tt.attempts)
+			}
+
+			if retry := h.IndexedAt == nil && h.NextAttemptAt != nil && h.NextAttemptAt.After(now); retry != tt.retry {
+				t.Errorf("indexHash() indexedAt = %v, nextAttemptAt = %v, want retry %v", h.IndexedAt, h.NextAttemptAt, tt.retry)
+			}
+		})
+	}
+}
+
+func Test_isHashDir(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "7/0c", want: true},
+		{path: "7", want: false},
+		{path: "70/c", want: false},
+		{path: "7/0q", want: false},
+		{path: "test", want: false},
+		{path: "7/0c/70c565ef460af43688b7ee6251028db9.jpg", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isHashDir(tt.path); got != tt.want {
+				t.Errorf("isHashDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashIndexer_walkNamespace(t *testing.T) {
+	dir := t.TempDir()
+	v, err := New(Config{Path: dir, Namespaces: []string{"test"}}, embedlog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-time.Hour)
+	files := []struct {
+		ns, hash string
+		old      bool
+	}{
+		{ns: "", hash: "70c565ef460af43688b7ee6251028db9"},
+		{ns: "", hash: "80c565ef460af43688b7ee6251028db9", old: true},
+		{ns: "test", hash: "90c565ef460af43688b7ee6251028db9"},
+	}
+	for _, f := range files {
+		fh := NewFileHash(f.hash, "jpg")
+		if err = os.MkdirAll(v.FullDir(f.ns, fh), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(v.FullFile(f.ns, fh), []byte(f.hash), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if f.old {
+			if err = os.Chtimes(v.FullDir(f.ns, fh), old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	hi := NewHashIndexer(embedlog.Logger{}, db.DB{}, nil, v, 1, 1, false, nil)
+	since := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		ns      string
+		since   *time.Time
+		want    string
+		skipped uint64
+	}{
+		{
+			name: "public",
+			ns:   NamespacePublic,
+			want: "70c565ef460af43688b7ee6251028db9;default;32;jpg\n80c565ef460af43688b7ee6251028db9;default;32;jpg\n",
+		},
+		{
+			name:    "public incremental",
+			ns:      NamespacePublic,
+			since:   &since,
+			want:    "70c565ef460af43688b7ee6251028db9;default;32;jpg\n",
+			skipped: 1,
+		},
+		{
+			name: "namespace",
+			ns:   "test",
+			want: "90c565ef460af43688b7ee6251028db9;test;32;jpg\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				buf bytes.Buffer
+				p   scanProgress
+			)
+			cw := csv.NewWriter(&buf)
+			cw.Comma = ';'
+
+			if err := hi.walkNamespace(context.Background(), tt.ns, tt.since, cw, &p); err != nil {
+				t.Fatal(err)
+			}
+			cw.Flush()
+
+			if buf.String() != tt.want || p.skipped.Load() != tt.skipped {
+				t.Errorf("walkNamespace() = %q, skipped %v, want %q, skipped %v", buf.String(), p.skipped.Load(), tt.want, tt.skipped)
+			}
+		})
+	}
+
+	// canceled scan
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = hi.walkNamespace(ctx, NamespacePublic, nil, csv.NewWriter(io.Discard), &scanProgress{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("walkNamespace() err = %v, want %v", err, context.Canceled)
+	}
+}
@@ -42,6 +42,41 @@ type ServerConfig struct {
 	// IndexNamespacesPriority defines namespace order for indexer.
 	// If set, hashes are processed in this order first.
 	IndexNamespacesPriority []string
+
+	// IndexIdleBackoff is max queue polling interval in seconds while queue is empty, default is 60.
+	// New hashes are indexed immediately via postgres LISTEN/NOTIFY.
+	IndexIdleBackoff int
+
+	// IndexMaxAttempts is max indexing attempts for transient errors (e.g. file not found), default is 5.
+	// After last attempt hash is marked as failed, see vfs.GetFailedHashes and vfs.RequeueFailedHashes.
+	IndexMaxAttempts int
+
+	// IndexRetryDelay is initial delay in seconds before next indexing attempt, doubled after each attempt, default is 60.
+	IndexRetryDelay int
+
+	// Watch adds files written into VFS.Path by other tools (e.g. rsync) into indexing queue using fsnotify. Requires Index.
+	Watch bool
+
+	// WatchDebounce is a delay in seconds after last file write before file is added, default is 2.
+	WatchDebounce int
+
+	// WatchScanInterval is incremental scan interval in seconds used if fsnotify watch limit is exceeded, default is 3600.
+	WatchScanInterval int
+
+	// StatsInterval is a namespace stats refresh interval in seconds, see vfs.NamespaceStats, default is 600.
+	StatsInterval int
+
+	// IndexFFprobePath is a path to ffprobe binary. If set, video and audio files are indexed: duration, resolution and codec.
+	IndexFFprobePath string
+
+	// IndexFFmpegPath is a path to ffmpeg binary. If set, poster frames are generated for video files.
+	IndexFFmpegPath string
+
+	// IndexPdftoppmPath is a path to pdftoppm binary. If set, first page thumbnails are generated for pdf files.
+	IndexPdftoppmPath string
+
+	// IndexPdfinfoPath is a path to pdfinfo binary. If set, total pages are detected for pdf files.
+	IndexPdfinfoPath string
 }
 
 type Config struct {
@@ -61,6 +96,10 @@ type App struct {
 	mon     *monitor.Monitor
 	echo    *echo.Echo
 	hi      *vfs.HashIndexer
+	hw      *vfs.HashWatcher
+	tp      *vfs.TrashPurger
+	sr      *vfs.StatsRefresher
+	ic      *indexerCollector
 }
 
 func New(appName string, sl embedlog.Logger, cfg Config, dbc *pg.DB) (*App, error) {
@@ -84,11 +123,27 @@ func New(appName string, sl embedlog.Logger, cfg Config, dbc *pg.DB) (*App, erro
 	if a.dbc != nil {
 		repo := db.NewVfsRepo(a.db)
 		a.repo = &repo
+
+		if cfg.VFS.TrashRetention > 0 {
+			a.tp = vfs.NewTrashPurger(a.Logger, a.vfs, a.repo, 0)
+		}
+
+		a.sr = vfs.NewStatsRefresher(a.Logger, a.vfs, a.repo, time.Duration(cfg.Server.StatsInterval)*time.Second)
 	}
 
 	// add services
 	if cfg.Server.Index {
-		a.hi = vfs.NewHashIndexer(a.Logger, a.db, a.repo, a.vfs, a.cfg.Server.IndexWorkers, a.cfg.Server.IndexBatchSize, a.cfg.Server.IndexBlurhash, a.cfg.Server.IndexNamespacesPriority)
+		a.hi = vfs.NewHashIndexer(a.Logger, a.db, a.repo, a.vfs, a.cfg.Server.IndexWorkers, a.cfg.Server.IndexBatchSize, a.cfg.Server.IndexBlurhash, a.cfg.Server.IndexNamespacesPriority,
+			vfs.WithIdleBackoff(time.Duration(a.cfg.Server.IndexIdleBackoff)*time.Second),
+			vfs.WithRetry(a.cfg.Server.IndexMaxAttempts, time.Duration(a.cfg.Server.IndexRetryDelay)*time.Second),
+			vfs.WithVideo(a.cfg.Server.IndexFFprobePath, a.cfg.Server.IndexFFmpegPath),
+			vfs.WithAudio(a.cfg.Server.IndexFFprobePath),
+			vfs.WithPDF(a.cfg.Server.IndexPdftoppmPath, a.cfg.Server.IndexPdfinfoPath),
+		)
+
+		if cfg.Server.Watch && a.repo != nil {
+			a.hw = vfs.NewHashWatcher(a.Logger, a.hi, a.repo, time.Duration(cfg.Server.WatchDebounce)*time.Second, time.Duration(cfg.Server.WatchScanInterval)*time.Second)
+		}
 	}
 
 	return a, nil
@@ -109,6 +164,18 @@ func (a *App) Run(ctx context.Context) error {
 		go a.hi.Start()
 	}
 
+	if a.hw != nil {
+		go a.hw.Start()
+	}
+
+	if a.tp != nil {
+		go a.tp.Start()
+	}
+
+	if a.sr != nil {
+		go a.sr.Start()
+	}
+
 	return a.runHTTPServer(ctx, a.cfg.Server.Host, a.cfg.Server.Port)
 }
 
@@ -131,7 +198,7 @@ func (a *App) registerAPIHandlers() {
 		zm.WithSentry(zm.DefaultServerName),
 	)
 
-	srv.Register("vfs", vfs.NewService(repo, a.vfs, a.dbc))
+	srv.Register("vfs", vfs.NewService(repo, a.vfs, a.dbc, a.hi, a.sr))
 
 	gen := rpcgen.FromSMD(srv.SMD())
 
@@ -148,12 +215,12 @@ func (a *App) registerAPIHandlers() {
 func (a *App) registerHandlers() {
 	// enable base handlers
 	a.echo.Any("/auth-token", a.issueTokenHandler)
-	a.echo.Any("/upload/hash", echo.WrapHandler(a.authMiddleware(a.vfs.HashUploadHandler(a.repo))))
+	a.echo.Any("/upload/hash", echo.WrapHandler(a.authMiddleware(a.vfs.HashUploadHandler(a.repo, a.hi))))
 	a.echo.Static(a.cfg.VFS.WebPath, a.cfg.VFS.Path)
 
 	// enabled indexer
 	if a.hi != nil {
-		a.echo.Any("/scan-files", a.hi.ScanFilesHandler)
+		a.echo.Any("/scan-files", a.hi.ScanFilesHandler, echo.WrapMiddleware(a.authMiddleware))
 		a.echo.GET("/preview/:ns/:file", a.hi.Preview)
 		a.echo.GET("/preview/:file", a.hi.Preview)
 	}
@@ -196,6 +263,22 @@ func (a *App) Shutdown(timeout time.Duration) {
 		a.mon.Close()
 	}
 
+	if a.hw != nil {
+		a.hw.Stop()
+	}
+
+	if a.tp != nil {
+		a.tp.Stop()
+	}
+
+	if a.sr != nil {
+		a.sr.Stop()
+	}
+
+	if a.ic != nil {
+		a.ic.stopRefresh()
+	}
+
 	if a.hi != nil {
 		a.hi.Stop()
 	}
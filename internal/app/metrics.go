@@ -1,7 +1,12 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmkteam/vfs"
 
 	monitor "github.com/hypnoglow/go-pg-monitor"
 	"github.com/hypnoglow/go-pg-monitor/gopgv10"
@@ -9,6 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/vmkteam/appkit"
+	"github.com/vmkteam/embedlog"
 )
 
 // registerMetrics is a function that initializes a.stat* variables and adds /metrics endpoint to echo.
@@ -25,6 +31,180 @@ func (a *App) registerMetrics() {
 		a.mon.Open()
 	}
 
+	if a.hi != nil {
+		a.ic = newIndexerCollector(a.hi, a.Logger)
+		prometheus.MustRegister(a.ic)
+		go a.ic.start()
+	}
+
+	if a.sr != nil {
+		prometheus.MustRegister(newStatsCollector(a.sr))
+	}
+
 	a.echo.Use(appkit.HTTPMetrics(appkit.DefaultServerName))
 	a.echo.Any("/metrics", echo.WrapHandler(promhttp.Handler()))
 }
+
+const (
+	indexerStatusTimeout  = 5 * time.Second
+	indexerStatusInterval = 30 * time.Second
+)
+
+// indexerCollector is a prometheus collector for indexer status.
+// Status aggregates whole vfsHashes table, so it is refreshed in background and last status is used on each scrape.
+type indexerCollector struct {
+	embedlog.Logger
+	hi *vfs.HashIndexer
+
+	mu       sync.RWMutex
+	st       *vfs.IndexerStatus
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	pending, retrying, failed      *prometheus.Desc
+	throughput, eta, scanning      *prometheus.Desc
+	lastScanScanned, lastScanAdded *prometheus.Desc
+}
+
+func newIndexerCollector(hi *vfs.HashIndexer, sl embedlog.Logger) *indexerCollector {
+	ns := []string{"namespace"}
+	return &indexerCollector{
+		Logger:          sl,
+		hi:              hi,
+		stop:            make(chan struct{}),
+		pending:         prometheus.NewDesc("vfs_indexer_pending", "Hashes in indexing queue.", ns, nil),
+		retrying:        prometheus.NewDesc("vfs_indexer_retrying", "Hashes waiting for next indexing attempt.", ns, nil),
+		failed:          prometheus.NewDesc("vfs_indexer_failed", "Hashes failed indexing after all attempts.", ns, nil),
+		throughput:      prometheus.NewDesc("vfs_indexer_throughput_per_minute", "Indexed hashes per minute for last 5 minutes.", nil, nil),
+		eta:             prometheus.NewDesc("vfs_indexer_eta_seconds", "Estimated seconds to process indexing queue.", nil, nil),
+		scanning:        prometheus.NewDesc("vfs_indexer_scanning", "Files scan is running.", nil, nil),
+		lastScanScanned: prometheus.NewDesc("vfs_indexer_last_scan_scanned", "Scanned files by last files scan.", nil, nil),
+		lastScanAdded:   prometheus.NewDesc("vfs_indexer_last_scan_added", "Added hashes by last files scan.", nil, nil),
+	}
+}
+
+func (c *indexerCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.pending, c.retrying, c.failed, c.throughput, c.eta, c.scanning, c.lastScanScanned, c.lastScanAdded} {
+		ch <- d
+	}
+}
+
+// start refreshes indexer status every indexerStatusInterval until stopped.
+func (c *indexerCollector) start() {
+	t := time.NewTicker(indexerStatusInterval)
+	defer t.Stop()
+
+	for {
+		c.refresh()
+
+		select {
+		case <-c.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (c *indexerCollector) stopRefresh() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+}
+
+func (c *indexerCollector) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), indexerStatusTimeout)
+	defer cancel()
+
+	st, err := c.hi.Status(ctx)
+	if err != nil {
+		c.Error(ctx, "indexer status failed", "err", err)
+		return
+	}
+
+	c.mu.Lock()
+	c.st = &st
+	c.mu.Unlock()
+}
+
+func (c *indexerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	st := c.st
+	c.mu.RUnlock()
+	if st == nil {
+		return
+	}
+
+	for _, q := range st.Namespaces {
+		ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(q.Pending), q.Namespace)
+		ch <- prometheus.MustNewConstMetric(c.retrying, prometheus.GaugeValue, float64(q.Retrying), q.Namespace)
+		ch <- prometheus.MustNewConstMetric(c.failed, prometheus.GaugeValue, float64(q.Failed), q.Namespace)
+	}
+
+	var eta, scanning float64
+	if st.ETA != nil {
+		eta = float64(*st.ETA)
+	}
+	if st.Scanning {
+		scanning = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.throughput, prometheus.GaugeValue, st.Throughput)
+	ch <- prometheus.MustNewConstMetric(c.eta, prometheus.GaugeValue, eta)
+	ch <- prometheus.MustNewConstMetric(c.scanning, prometheus.GaugeValue, scanning)
+
+	if st.LastScan != nil {
+		ch <- prometheus.MustNewConstMetric(c.lastScanScanned, prometheus.GaugeValue, float64(st.LastScan.Scanned))
+		ch <- prometheus.MustNewConstMetric(c.lastScanAdded, prometheus.GaugeValue, float64(st.LastScan.Added))
+	}
+}
+
+// statsCollector is a prometheus collector for namespace stats, last refreshed stats are used on each scrape.
+type statsCollector struct {
+	sr *vfs.StatsRefresher
+
+	hashes, bytes, avgWidth, avgHeight *prometheus.Desc
+	indexed, pending                   *prometheus.Desc
+	dailyHashes, dailyBytes            *prometheus.Desc
+}
+
+func newStatsCollector(sr *vfs.StatsRefresher) *statsCollector {
+	ns, ext := []string{"namespace"}, []string{"namespace", "extension"}
+	return &statsCollector{
+		sr:          sr,
+		hashes:      prometheus.NewDesc("vfs_namespace_hashes", "Hashes in namespace by extension.", ext, nil),
+		bytes:       prometheus.NewDesc("vfs_namespace_bytes", "Hashes size in bytes in namespace by extension.", ext, nil),
+		avgWidth:    prometheus.NewDesc("vfs_namespace_avg_width", "Average width of hashes with dimensions in namespace by extension.", ext, nil),
+		avgHeight:   prometheus.NewDesc("vfs_namespace_avg_height", "Average height of hashes with dimensions in namespace by extension.", ext, nil),
+		indexed:     prometheus.NewDesc("vfs_namespace_indexed", "Indexed hashes in namespace by extension.", ext, nil),
+		pending:     prometheus.NewDesc("vfs_namespace_pending", "Not indexed hashes in namespace by extension.", ext, nil),
+		dailyHashes: prometheus.NewDesc("vfs_namespace_daily_growth_hashes", "Average created hashes per day for last 30 days.", ns, nil),
+		dailyBytes:  prometheus.NewDesc("vfs_namespace_daily_growth_bytes", "Average created hashes size in bytes per day for last 30 days.", ns, nil),
+	}
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.hashes, c.bytes, c.avgWidth, c.avgHeight, c.indexed, c.pending, c.dailyHashes, c.dailyBytes} {
+		ch <- d
+	}
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.sr.Stats()
+	if st == nil {
+		return
+	}
+
+	for _, ns := range st.Namespaces {
+		for _, e := range ns.Extensions {
+			ch <- prometheus.MustNewConstMetric(c.hashes, prometheus.GaugeValue, float64(e.Hashes), ns.Namespace, e.Extension)
+			ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(e.FilesSize), ns.Namespace, e.Extension)
+			ch <- prometheus.MustNewConstMetric(c.avgWidth, prometheus.GaugeValue, e.AvgWidth, ns.Namespace, e.Extension)
+			ch <- prometheus.MustNewConstMetric(c.avgHeight, prometheus.GaugeValue, e.AvgHeight, ns.Namespace, e.Extension)
+			ch <- prometheus.MustNewConstMetric(c.indexed, prometheus.GaugeValue, float64(e.Indexed), ns.Namespace, e.Extension)
+			ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(e.Pending), ns.Namespace, e.Extension)
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.dailyHashes, prometheus.GaugeValue, ns.DailyHashes, ns.Namespace)
+		ch <- prometheus.MustNewConstMetric(c.dailyBytes, prometheus.GaugeValue, ns.DailyFilesSize, ns.Namespace)
+	}
+}
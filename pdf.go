@@ -0,0 +1,116 @@
+package vfs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	thumbnailKind = "thumb"
+	thumbnailSize = 512
+)
+
+var errNoPages = errors.New("pages not found")
+
+// pdfIndexer gets total pages via pdfinfo, renders first page thumbnail and calculates its size and blurhash.
+type pdfIndexer struct {
+	pdftoppmPath string
+	pdfinfoPath  string
+	calcBlurHash bool
+}
+
+func (pi pdfIndexer) Index(ctx context.Context, f IndexedFile, info *HashInfo) error {
+	if pi.pdfinfoPath != "" {
+		pages, err := probePDF(ctx, pi.pdfinfoPath, f.Path)
+		if err != nil {
+			return err
+		}
+		info.Params.Pages = pages
+	}
+
+	// render first page
+	thumbnail, thumbnailPath := f.DerivedFile(thumbnailKind)
+	if err := renderThumbnail(ctx, pi.pdftoppmPath, f.Path, thumbnailPath); err != nil {
+		return err
+	}
+	info.Params.Thumbnail = thumbnail
+
+	img, err := decodeImage(thumbnailPath)
+	if err != nil {
+		return err
+	}
+
+	info.Width = img.Bounds().Dx()
+	info.Height = img.Bounds().Dy()
+	info.BlurHash, err = blurHash(img, pi.calcBlurHash)
+	return err
+}
+
+// probePDF returns total pages of pdf document using pdfinfo.
+func probePDF(ctx context.Context, pdfinfoPath, filename string) (int, error) {
+	//nolint:gosec
+	out, err := exec.CommandContext(ctx, pdfinfoPath, filename).Output()
+	if err != nil {
+		return 0, fmt.Errorf("pdfinfo failed: %w", execError(err))
+	}
+
+	return parsePDFInfo(out)
+}
+
+// parsePDFInfo parses pdfinfo output and returns total pages.
+func parsePDFInfo(data []byte) (int, error) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok || key != "Pages" {
+			continue
+		}
+
+		pages, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid pages: %w", err)
+		}
+		return pages, nil
+	}
+
+	if err := sc.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, errNoPages
+}
+
+// renderThumbnail renders first page of pdf document as jpeg into dst file.
+func renderThumbnail(ctx context.Context, pdftoppmPath, filename, dst string) error {
+	// pdftoppm adds .jpg to output prefix
+	prefix := strings.TrimSuffix(dst, ".jpg") + ".tmp"
+	tmp := prefix + ".jpg"
+
+	//nolint:gosec
+	_, err := exec.CommandContext(ctx, pdftoppmPath,
+		"-jpeg",
+		"-f", "1",
+		"-l", "1",
+		"-singlefile",
+		"-scale-to", strconv.Itoa(thumbnailSize),
+		filename,
+		prefix,
+	).Output()
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("pdftoppm failed: %w", execError(err))
+	}
+
+	if err = os.Chmod(tmp, defaultHashFileModePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, dst)
+}
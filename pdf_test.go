@@ -0,0 +1,37 @@
+package vfs
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_parsePDFInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int
+		wantErr error
+	}{
+		{
+			name: "pdfinfo output",
+			data: "Title:           Report\nProducer:        LibreOffice 7.3\nTagged:          no\nPages:           12\nEncrypted:       no\nPage size:       595.304 x 841.89 pts (A4)\n",
+			want: 12,
+		},
+		{
+			name:    "no pages",
+			data:    "Title:           Report\n",
+			wantErr: errNoPages,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePDFInfo([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parsePDFInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parsePDFInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
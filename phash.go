@@ -0,0 +1,69 @@
+package vfs
+
+import (
+	"image"
+)
+
+const (
+	dHashWidth       = 9
+	dHashHeight      = 8
+	maxDHashSamples  = 16 // max samples per cell side
+	maxHashDistance  = 64
+	maxSimilarHashes = 100
+)
+
+// dHash calculates 64-bit perceptual difference hash.
+// Image is reduced to 9x8 grayscale cells and each cell is compared with its right neighbour.
+func dHash(img image.Image) uint64 {
+	var cells [dHashHeight][dHashWidth]float64
+
+	b := img.Bounds()
+	for cy := range dHashHeight {
+		y0, y1 := cellRange(b.Min.Y, b.Dy(), cy, dHashHeight)
+		for cx := range dHashWidth {
+			x0, x1 := cellRange(b.Min.X, b.Dx(), cx, dHashWidth)
+			cells[cy][cx] = averageLuma(img, x0, x1, y0, y1)
+		}
+	}
+
+	var h uint64
+	for cy := range dHashHeight {
+		for cx := range dHashWidth - 1 {
+			h <<= 1
+			if cells[cy][cx] < cells[cy][cx+1] {
+				h |= 1
+			}
+		}
+	}
+
+	return h
+}
+
+// cellRange returns [start, end) range of i-th cell from n cells.
+func cellRange(minV, size, i, n int) (int, int) {
+	start, end := minV+i*size/n, minV+(i+1)*size/n
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
+
+// averageLuma returns average luma of image rect, large rects are sampled.
+func averageLuma(img image.Image, x0, x1, y0, y1 int) float64 {
+	stepX, stepY := max((x1-x0)/maxDHashSamples, 1), max((y1-y0)/maxDHashSamples, 1)
+
+	var sum float64
+	var n int
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
@@ -0,0 +1,39 @@
+package vfs
+
+import (
+	"image"
+	"image/color"
+	"math/bits"
+	"testing"
+)
+
+func gradientImage(w, h int, reverse bool) image.Image {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			v := uint8(x * 255 / w)
+			if reverse {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v ^ uint8(y*64/h)})
+		}
+	}
+	return img
+}
+
+func Test_dHash(t *testing.T) {
+	original := dHash(gradientImage(640, 480, false))
+	resized := dHash(gradientImage(160, 120, false))
+	reversed := dHash(gradientImage(640, 480, true))
+
+	if d := bits.OnesCount64(original ^ resized); d > 4 {
+		t.Errorf("resized distance = %d, want <= 4", d)
+	}
+
+	if d := bits.OnesCount64(original ^ reversed); d < 32 {
+		t.Errorf("reversed distance = %d, want >= 32", d)
+	}
+
+	// tiny images
+	dHash(image.NewGray(image.Rect(0, 0, 1, 1)))
+}
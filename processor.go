@@ -0,0 +1,117 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"image"
+	"os"
+	"strings"
+
+	"github.com/bbrks/go-blurhash"
+)
+
+var ErrUnsupportedMimeType = errors.New("unsupported mime type")
+
+// Indexer extracts metadata from a hash file. Indexers are registered per mime type via WithIndexer.
+// Common fields are saved into vfsHashes columns, all other fields should be added to HashInfo.Params.
+type Indexer interface {
+	Index(ctx context.Context, f IndexedFile, info *HashInfo) error
+}
+
+// IndexedFile is a hash file passed to Indexer.
+type IndexedFile struct {
+	Namespace string   // fs namespace, empty for default
+	RelPath   string   // path relative to namespace, e.g. "7/0c/70c565ef460af43688b7ee6251028db9.jpg"
+	Path      string   // full path on fs
+	MimeType  string   // detected mime type
+	File      *os.File // opened file, rewound to start for each indexer
+
+	vfs VFS
+}
+
+// DerivedFile returns relative and full paths for a file generated from hash file, e.g. poster or thumbnail.
+func (f IndexedFile) DerivedFile(kind string) (relPath, fullPath string) {
+	relPath = derivedFile(f.RelPath, kind)
+	return relPath, f.vfs.Path(f.Namespace, relPath)
+}
+
+type indexerEntry struct {
+	mimeType string
+	indexer  Indexer
+}
+
+// match checks mime type against entry mime type: exact, wildcard "image/*" or any "*".
+func (e indexerEntry) match(mType string) bool {
+	switch {
+	case e.mimeType == "*", e.mimeType == mType:
+		return true
+	case strings.HasSuffix(e.mimeType, "/*"):
+		return strings.HasPrefix(mType, strings.TrimSuffix(e.mimeType, "*"))
+	}
+
+	return false
+}
+
+// imageIndexer detects image size, color palette, perceptual hash and calculates blurhash.
+type imageIndexer struct {
+	calcBlurHash bool
+}
+
+func (ii imageIndexer) Index(_ context.Context, f IndexedFile, info *HashInfo) error {
+	img, _, err := image.Decode(f.File)
+	if err != nil {
+		return err
+	}
+
+	info.Width = img.Bounds().Dx()
+	info.Height = img.Bounds().Dy()
+	info.Palette = colorPalette(img, paletteSize)
+	if len(info.Palette) > 0 {
+		info.Color = info.Palette[0]
+	}
+	ph := int64(dHash(img)) //nolint:gosec
+	info.PHash = &ph
+	info.BlurHash, err = blurHash(img, ii.calcBlurHash)
+
+	return err
+}
+
+// audioIndexer gets audio duration and codec via ffprobe.
+type audioIndexer struct {
+	ffprobePath string
+}
+
+func (ai audioIndexer) Index(ctx context.Context, f IndexedFile, info *HashInfo) error {
+	mi, err := probeMedia(ctx, ai.ffprobePath, f.Path, audioStream)
+	if err != nil {
+		return err
+	}
+
+	info.Params.Duration = mi.Duration
+	info.Params.Codec = mi.Codec
+
+	return nil
+}
+
+// decodeImage opens and decodes image file.
+func decodeImage(filename string) (image.Image, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// blurHash calculates blurhash if enabled.
+func blurHash(img image.Image, enabled bool) (string, error) {
+	if !enabled {
+		return "", nil
+	}
+
+	return blurhash.Encode(4, 3, img)
+}
@@ -0,0 +1,135 @@
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmkteam/vfs/db"
+
+	"github.com/vmkteam/embedlog"
+)
+
+func Test_indexerEntry_match(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		mType    string
+		want     bool
+	}{
+		{mimeType: "*", mType: "text/plain", want: true},
+		{mimeType: "image/png", mType: "image/png", want: true},
+		{mimeType: "image/png", mType: "image/jpeg", want: false},
+		{mimeType: "image/*", mType: "image/jpeg", want: true},
+		{mimeType: "image/*", mType: "video/mp4", want: false},
+		{mimeType: "video/*", mType: "video", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mimeType+" "+tt.mType, func(t *testing.T) {
+			if got := (indexerEntry{mimeType: tt.mimeType}).match(tt.mType); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type extraIndexer struct{}
+
+func (extraIndexer) Index(_ context.Context, f IndexedFile, info *HashInfo) error {
+	info.Params.Extra = map[string]interface{}{"mimeType": f.MimeType}
+	return nil
+}
+
+func TestHashIndexer_IndexFile(t *testing.T) {
+	dir := t.TempDir()
+	v, err := New(Config{Path: dir}, embedlog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNk+A8AAQUBAScY42YAAAAASUVORK5CYII=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fh := NewFileHash("70c565ef460af43688b7ee6251028db9", "png")
+	if err = os.MkdirAll(filepath.Join(dir, fh.Dir()), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, fh.File()), data, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, "test.txt"), []byte("plain text"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	hi := NewHashIndexer(embedlog.Logger{}, db.DB{}, nil, v, 1, 1, true, nil, WithIndexer(extraIndexer{}, "image/png"))
+
+	info, err := hi.IndexFile(NamespacePublic, fh.File())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Width != 1 || info.Height != 1 || info.BlurHash == "" || info.FileSize != int64(len(data)) {
+		t.Errorf("IndexFile() = %+v", info)
+	}
+
+	if p := info.params(); p == nil || p.Extra["mimeType"] != "image/png" {
+		t.Errorf("params() = %+v", p)
+	}
+
+	// unsupported files
+	if _, err = hi.IndexFile(NamespacePublic, "test.txt"); !errors.Is(err, ErrUnsupportedMimeType) {
+		t.Errorf("IndexFile() err = %v, want %v", err, ErrUnsupportedMimeType)
+	}
+}
+
+func TestVFS_HashUploadHandler_IndexSync(t *testing.T) {
+	dir := t.TempDir()
+	v, err := New(Config{Path: dir, MaxFileSize: 1 << 20, MimeTypes: []string{"*"}, Extensions: []string{"*"}}, embedlog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNk+A8AAQUBAScY42YAAAAASUVORK5CYII=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hi := NewHashIndexer(embedlog.Logger{}, db.DB{}, nil, v, 1, 1, true, nil)
+	handler := v.HashUploadHandler(nil, hi)
+
+	tests := []struct {
+		query   string
+		indexed bool
+	}{
+		{query: "?ext=png", indexed: false},
+		{query: "?ext=png&index=sync", indexed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/upload/hash"+tt.query, bytes.NewReader(data))
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("code = %v, body = %s", w.Code, w.Body.String())
+			}
+
+			var ur UploadResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &ur); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ur.Width == 1 && ur.Height == 1 && ur.Blurhash != ""; got != tt.indexed {
+				t.Errorf("HashUploadHandler() = %+v, indexed %v", ur, tt.indexed)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vmkteam/vfs/db"
+)
+
+var ErrQuotaExceeded = errors.New("folder quota exceeded")
+
+// checkQuota checks that adding files with total size into folder does not exceed quotas of folder and its parents.
+// Negative or zero files and size are not checked, e.g. for file replacement with smaller file.
+// Folders with quotas are locked until the end of transaction, so repo must be in transaction with following insert.
+func (v VFS) checkQuota(ctx context.Context, repo db.VfsRepo, folderID, files int, size int64) error {
+	branch, err := repo.FolderBranch(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		limited []db.VfsFolder
+		ids     []int
+	)
+	for _, fl := range branch {
+		if fl.QuotaBytes != nil || fl.QuotaFiles != nil {
+			limited = append(limited, fl)
+			ids = append(ids, fl.ID)
+		}
+	}
+	if len(limited) == 0 {
+		return nil
+	}
+
+	if err = repo.LockVfsFolders(ctx, ids); err != nil {
+		return err
+	}
+
+	usage, err := repo.FoldersUsage(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, fl := range limited {
+		u := usage[fl.ID]
+		if files > 0 && fl.QuotaFiles != nil && u.Files+files > *fl.QuotaFiles {
+			return fmt.Errorf("%w: folder %d files %d of %d", ErrQuotaExceeded, fl.ID, u.Files, *fl.QuotaFiles)
+		}
+		if size > 0 && fl.QuotaBytes != nil && u.FilesSize+size > *fl.QuotaBytes {
+			return fmt.Errorf("%w: folder %d size %d of %d bytes", ErrQuotaExceeded, fl.ID, u.FilesSize, *fl.QuotaBytes)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,429 @@
+package vfs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	// maxScanJobs is total scan jobs kept in memory, older finished jobs are removed.
+	maxScanJobs = 20
+
+	ScanJobRunning  = "running"
+	ScanJobDone     = "done"
+	ScanJobFailed   = "failed"
+	ScanJobCanceled = "canceled"
+)
+
+var (
+	ErrScanRunning    = errors.New("already scanning")
+	ErrFolderNotFound = errors.New("folder not found")
+	ErrNoFilesFound   = errors.New("no files found, check VFS.Path")
+)
+
+// ScanOptions is a files scan options.
+type ScanOptions struct {
+	// Namespaces is a list of namespaces for scan, all namespaces are scanned if empty.
+	// DefaultNamespace or empty string is used for public namespace.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Incremental skips hash directories not modified since last completed scan of namespace.
+	Incremental bool `json:"incremental"`
+
+	// Files scans YYYYMM directories of public namespace instead of hashes and updates vfsFiles.fileExists.
+	// Namespaces and Incremental are ignored for files scan.
+	Files bool `json:"files,omitempty"`
+
+	// UnsortedFolderID is a folder for files not found in vfsFiles, unknown files are not imported if empty.
+	UnsortedFolderID *int `json:"unsortedFolderId,omitempty"`
+}
+
+// ScanJob is a background files scan snapshot.
+type ScanJob struct {
+	ID          string       `json:"id"`
+	Status      string       `json:"status"` // running, done, failed or canceled
+	Options     ScanOptions  `json:"options"`
+	StartedAt   time.Time    `json:"startedAt"`
+	FinishedAt  *time.Time   `json:"finishedAt,omitempty"`
+	Walked      uint64       `json:"walked"`               // total walked files
+	SkippedDirs uint64       `json:"skippedDirs"`          // not modified hash directories skipped by incremental scan
+	CurrentDir  string       `json:"currentDir,omitempty"` // current directory relative to vfs path
+	Results     *ScanResults `json:"results,omitempty"`    // results for finished job
+	Error       string       `json:"error,omitempty"`
+}
+
+// scanProgress is a files scan progress updated by walkFn.
+type scanProgress struct {
+	walked  atomic.Uint64
+	skipped atomic.Uint64
+	dir     atomic.String
+}
+
+// scanJob is a running or finished background files scan.
+type scanJob struct {
+	id        string
+	opts      ScanOptions
+	startedAt time.Time
+	cancel    context.CancelFunc
+	progress  scanProgress
+
+	mu         sync.Mutex
+	status     string
+	finishedAt *time.Time
+	results    *ScanResults
+	err        error
+}
+
+func newScanJob(opts ScanOptions, cancel context.CancelFunc) (*scanJob, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return &scanJob{id: hex.EncodeToString(b), opts: opts, startedAt: time.Now(), cancel: cancel, status: ScanJobRunning}, nil
+}
+
+// finish sets job results and status.
+func (j *scanJob) finish(r ScanResults, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	j.finishedAt, j.err = &now, err
+	switch {
+	case errors.Is(err, context.Canceled):
+		j.status = ScanJobCanceled
+	case err != nil:
+		j.status = ScanJobFailed
+	default:
+		j.status = ScanJobDone
+		j.results = &r
+	}
+}
+
+// snapshot returns current job state.
+func (j *scanJob) snapshot() ScanJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job := ScanJob{
+		ID:          j.id,
+		Status:      j.status,
+		Options:     j.opts,
+		StartedAt:   j.startedAt,
+		FinishedAt:  j.finishedAt,
+		Walked:      j.progress.walked.Load(),
+		SkippedDirs: j.progress.skipped.Load(),
+		CurrentDir:  j.progress.dir.Load(),
+		Results:     j.results,
+	}
+	if j.err != nil {
+		job.Error = j.err.Error()
+	}
+
+	return job
+}
+
+// isRunning checks that job is not finished.
+func (j *scanJob) isRunning() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.status == ScanJobRunning
+}
+
+// scanJobs is a list of last scan jobs, newest first.
+type scanJobs struct {
+	mu   sync.Mutex
+	list []*scanJob
+}
+
+// add adds new job and removes oldest finished jobs over maxScanJobs.
+func (sj *scanJobs) add(j *scanJob) {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	sj.list = append([]*scanJob{j}, sj.list...)
+	if len(sj.list) > maxScanJobs {
+		sj.list = sj.list[:maxScanJobs]
+	}
+}
+
+// job returns job by id or nil.
+func (sj *scanJobs) job(id string) *scanJob {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	for _, j := range sj.list {
+		if j.id == id {
+			return j
+		}
+	}
+
+	return nil
+}
+
+// all returns all jobs snapshots.
+func (sj *scanJobs) all() []ScanJob {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	list := make([]ScanJob, 0, len(sj.list))
+	for _, j := range sj.list {
+		list = append(list, j.snapshot())
+	}
+
+	return list
+}
+
+// cancelAll cancels all running jobs.
+func (sj *scanJobs) cancelAll() {
+	sj.mu.Lock()
+	defer sj.mu.Unlock()
+
+	for _, j := range sj.list {
+		j.cancel()
+	}
+}
+
+// StartScan starts files scan in background and returns scan job. Only one scan could be run at the same time.
+func (hi HashIndexer) StartScan(opts ScanOptions) (ScanJob, error) {
+	if err := hi.checkScanOptions(context.Background(), opts); err != nil {
+		return ScanJob{}, err
+	}
+
+	if !hi.scanning.CompareAndSwap(false, true) {
+		return ScanJob{}, ErrScanRunning
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j, err := newScanJob(opts, cancel)
+	if err != nil {
+		cancel()
+		hi.scanning.Store(false)
+		return ScanJob{}, err
+	}
+	hi.scanJobs.add(j)
+
+	go func() {
+		defer func() {
+			cancel()
+			hi.scanning.Store(false)
+		}()
+
+		r, err := hi.scanFiles(ctx, opts, &j.progress)
+		j.finish(r, err)
+		hi.PrintOrErr(ctx, "scan files", err, "job", j.id, "scanned", r.Scanned, "added", r.Added, "walked", j.progress.walked.Load(), "skippedDirs", j.progress.skipped.Load())
+	}()
+
+	return j.snapshot(), nil
+}
+
+// checkScanOptions validates scan namespaces and unsorted folder.
+func (hi HashIndexer) checkScanOptions(ctx context.Context, opts ScanOptions) error {
+	if opts.Files {
+		if opts.UnsortedFolderID == nil {
+			return nil
+		}
+
+		folder, err := hi.repo.VfsFolderByID(ctx, *opts.UnsortedFolderID)
+		if err != nil {
+			return err
+		} else if folder == nil {
+			return fmt.Errorf("%w: %d", ErrFolderNotFound, *opts.UnsortedFolderID)
+		}
+
+		return nil
+	}
+
+	_, err := hi.scanNamespaces(opts.Namespaces)
+	return err
+}
+
+// ScanJob returns scan job by id.
+func (hi HashIndexer) ScanJob(id string) (ScanJob, bool) {
+	j := hi.scanJobs.job(id)
+	if j == nil {
+		return ScanJob{}, false
+	}
+
+	return j.snapshot(), true
+}
+
+// ScanJobs returns last scan jobs, newest first.
+func (hi HashIndexer) ScanJobs() []ScanJob {
+	return hi.scanJobs.all()
+}
+
+// CancelScan cancels running scan job. Returns false if job is not found or already finished.
+func (hi HashIndexer) CancelScan(id string) bool {
+	j := hi.scanJobs.job(id)
+	if j == nil || !j.isRunning() {
+		return false
+	}
+
+	j.cancel()
+	return true
+}
+
+// isFilesDir checks that relPath is a YYYYMM directory for vfsFiles, see createFile.
+func isFilesDir(relPath string) bool {
+	if len(relPath) != len(filesDirLayout) {
+		return false
+	}
+
+	_, err := time.Parse(filesDirLayout, relPath)
+	return err == nil
+}
+
+// walkVfsFiles returns relative paths of all files in YYYYMM directories of public namespace.
+func (hi HashIndexer) walkVfsFiles(ctx context.Context, p *scanProgress) (map[string]struct{}, error) {
+	files := make(map[string]struct{})
+	root := hi.vfs.Path(NamespacePublic, "")
+	dirs, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, d := range dirs {
+		if !d.IsDir() || !isFilesDir(d.Name()) {
+			continue
+		}
+
+		p.dir.Store(d.Name())
+		err = filepath.WalkDir(filepath.Join(root, d.Name()), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			} else if ctx.Err() != nil {
+				return ctx.Err()
+			} else if d.IsDir() {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			p.walked.Inc()
+			files[relPath] = struct{}{}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// scanVfsFiles reconciles vfsFiles.fileExists with files in YYYYMM directories of public namespace.
+// Files not found in vfsFiles are imported into unsorted folder if it is set.
+func (hi HashIndexer) scanVfsFiles(ctx context.Context, opts ScanOptions, p *scanProgress) (r ScanResults, err error) {
+	start := time.Now()
+	files, err := hi.walkVfsFiles(ctx, p)
+	if err != nil {
+		return r, err
+	}
+	r.Scanned = uint64(len(files))
+
+	// files created during walk are skipped: they could be not walked yet
+	list, err := hi.repo.VfsFilePaths(ctx, start)
+	if err != nil {
+		return r, err
+	}
+
+	// prior file versions are not unknown files
+	versions, err := hi.repo.FileVersionPaths(ctx)
+	if err != nil {
+		return r, err
+	}
+	for _, vp := range versions {
+		delete(files, vp)
+	}
+
+	// deleted files are kept on disk until trash purge, they are not unknown files
+	var (
+		missing, restored []int
+		existing          int
+	)
+	for _, f := range list {
+		_, ok := files[f.Path]
+		delete(files, f.Path)
+		if f.StatusID == db.StatusDeleted || f.IsNew {
+			continue
+		}
+
+		switch {
+		case f.FileExists:
+			existing++
+			if !ok && !hi.vfs.fileExists(f.Path) {
+				missing = append(missing, f.ID)
+			}
+		case ok:
+			restored = append(restored, f.ID)
+		}
+	}
+
+	// VFS.Path could be not mounted
+	if r.Scanned == 0 && existing > 0 {
+		return r, ErrNoFilesFound
+	}
+
+	n, err := hi.repo.SetVfsFilesExist(ctx, missing, false)
+	if err != nil {
+		return r, err
+	}
+	r.Missing = uint64(n)
+
+	if n, err = hi.repo.SetVfsFilesExist(ctx, restored, true); err != nil {
+		return r, err
+	}
+	r.Restored = uint64(n)
+
+	// import unknown files
+	if opts.UnsortedFolderID != nil {
+		paths := slices.Sorted(maps.Keys(files))
+		for _, relPath := range paths {
+			if ctx.Err() != nil {
+				return r, ctx.Err()
+			}
+
+			// file could be uploaded during scan: it is written after start or its vfsFiles row is committed after VfsFilePaths
+			if fi, err := os.Stat(hi.vfs.Path(NamespacePublic, relPath)); err != nil || fi.ModTime().After(start) {
+				continue
+			}
+			if exists, err := hi.repo.VfsFilePathExists(ctx, relPath); err != nil {
+				return r, err
+			} else if exists {
+				continue
+			}
+
+			if _, err = hi.vfs.importFile(ctx, *hi.repo, *opts.UnsortedFolderID, relPath); err != nil {
+				return r, fmt.Errorf("import %s failed: %w", relPath, err)
+			}
+			r.Imported++
+		}
+	}
+
+	r.Duration = time.Since(start)
+	hi.stats.setLastScan(r, time.Now())
+
+	return r, nil
+}
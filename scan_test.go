@@ -0,0 +1,170 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/vmkteam/vfs/db"
+
+	"github.com/vmkteam/embedlog"
+)
+
+func Test_scanJob_finish(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status string
+	}{
+		{name: "done", status: ScanJobDone},
+		{name: "failed", err: errors.New("copy failed"), status: ScanJobFailed},
+		{name: "canceled", err: context.Canceled, status: ScanJobCanceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j, err := newScanJob(ScanOptions{}, func() {})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !j.isRunning() {
+				t.Fatalf("isRunning() = false for new job")
+			}
+
+			j.progress.walked.Store(10)
+			j.finish(ScanResults{Scanned: 5, Added: 1}, tt.err)
+
+			job := j.snapshot()
+			if job.Status != tt.status || job.FinishedAt == nil || job.Walked != 10 || j.isRunning() {
+				t.Errorf("snapshot() = %+v, want status %v", job, tt.status)
+			}
+			if (job.Results != nil) != (tt.err == nil) || (job.Error != "") != (tt.err != nil) {
+				t.Errorf("snapshot() results = %v, error = %q", job.Results, job.Error)
+			}
+		})
+	}
+}
+
+func Test_scanJobs(t *testing.T) {
+	var (
+		sj       scanJobs
+		canceled int
+		ids      []string
+	)
+
+	for i := 0; i < maxScanJobs+5; i++ {
+		j, err := newScanJob(ScanOptions{}, func() { canceled++ })
+		if err != nil {
+			t.Fatal(err)
+		}
+		sj.add(j)
+		ids = append(ids, j.id)
+	}
+
+	list := sj.all()
+	if len(list) != maxScanJobs || list[0].ID != ids[len(ids)-1] {
+		t.Errorf("all() = %v jobs, first %v", len(list), list[0].ID)
+	}
+
+	if sj.job(ids[0]) != nil || sj.job(ids[len(ids)-1]) == nil {
+		t.Errorf("job() returned removed job or not found last job")
+	}
+
+	sj.cancelAll()
+	if canceled != maxScanJobs {
+		t.Errorf("cancelAll() canceled %v jobs, want %v", canceled, maxScanJobs)
+	}
+}
+
+func Test_isFilesDir(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "202401", want: true},
+		{path: "202413", want: false},
+		{path: "2024", want: false},
+		{path: "test", want: false},
+		{path: "7/0c", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isFilesDir(tt.path); got != tt.want {
+				t.Errorf("isFilesDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashIndexer_walkVfsFiles(t *testing.T) {
+	dir := t.TempDir()
+	v, err := New(Config{Path: dir, Namespaces: []string{"test"}}, embedlog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{"202401/1_1.jpg", "202402/sub/1_2.png", "test/202401/1_3.jpg", "7/0c/70c565ef460af43688b7ee6251028db9.jpg", "temp123"} {
+		path := v.Path(NamespacePublic, f)
+		if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(path, []byte(f), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hi := NewHashIndexer(embedlog.Logger{}, db.DB{}, nil, v, 1, 1, false, nil)
+	var p scanProgress
+	files, err := hi.walkVfsFiles(context.Background(), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, want := slices.Sorted(maps.Keys(files)), []string{"202401/1_1.jpg", "202402/sub/1_2.png"}
+	if !reflect.DeepEqual(got, want) || p.walked.Load() != 2 {
+		t.Errorf("walkVfsFiles() = %v, walked %v, want %v", got, p.walked.Load(), want)
+	}
+
+	// canceled scan
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = hi.walkVfsFiles(ctx, &scanProgress{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("walkVfsFiles() err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestVFS_fileNamespace(t *testing.T) {
+	v, err := New(Config{Path: t.TempDir(), Namespaces: []string{"test"}}, embedlog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{"202401/1_1.jpg", "test/202401/1_2.jpg"} {
+		path := v.Path(NamespacePublic, f)
+		if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(path, []byte(f), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path string
+		ns   string
+		ok   bool
+	}{
+		{path: "202401/1_1.jpg", ns: NamespacePublic, ok: true},
+		{path: "202401/1_2.jpg", ns: "test", ok: true},
+		{path: "202401/1_3.jpg", ns: NamespacePublic, ok: false},
+	}
+	for _, tt := range tests {
+		if ns, ok := v.fileNamespace(tt.path); ns != tt.ns || ok != tt.ok {
+			t.Errorf("fileNamespace(%v) = %v, %v, want %v, %v", tt.path, ns, ok, tt.ns, tt.ok)
+		}
+	}
+}
@@ -2,11 +2,15 @@ package vfs
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,9 +25,33 @@ var (
 	ErrNotFound     = newError(http.StatusNotFound)
 	ErrInvalidSort  = zenrpc.NewStringError(http.StatusBadRequest, "invalid sort field")
 	ErrInvalidInput = zenrpc.NewStringError(http.StatusBadRequest, "invalid user input")
+
+	ErrIndexerDisabled = zenrpc.NewStringError(http.StatusServiceUnavailable, "indexer is disabled")
+	ErrAltRequired     = zenrpc.NewStringError(http.StatusUnprocessableEntity, "alt text is required")
+	ErrStatsNotReady   = zenrpc.NewStringError(http.StatusServiceUnavailable, "stats are not ready")
+)
+
+var (
+	filenameRegex = regexp.MustCompile(`^([0-9a-z_-])+\.([0-9a-z])+$`)
+	langRegex     = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
 )
 
-var filenameRegex = regexp.MustCompile(`^([0-9a-z_-])+\.([0-9a-z])+$`)
+const (
+	maxHashInfoList = 1000
+
+	// reindexBatchSize is total hashes updated by single query in Reindex.
+	reindexBatchSize = 1000
+
+	// maxSearchPageSize is max page size for SearchFiles.
+	maxSearchPageSize = 500
+
+	// maxBulkFiles is max files for bulk tags and metadata updates and files copy.
+	maxBulkFiles = 1000
+
+	maxTagLength      = 64
+	maxMetaKeyLength  = 64
+	maxFileTextLength = 1024
+)
 
 func newError(code int) *zenrpc.Error {
 	return zenrpc.NewStringError(code, http.StatusText(code))
@@ -39,10 +67,21 @@ type Service struct {
 	dbc  *pg.DB
 	repo db.VfsRepo
 	vfs  VFS
+	hi   *HashIndexer
+	sr   *StatsRefresher
+}
+
+// NewService returns vfs rpc service, hi and sr are optional.
+func NewService(repo db.VfsRepo, vfs VFS, dbc *pg.DB, hi *HashIndexer, sr *StatsRefresher) Service {
+	return Service{repo: repo, vfs: vfs, dbc: dbc, hi: hi, sr: sr}
 }
 
-func NewService(repo db.VfsRepo, vfs VFS, dbc *pg.DB) Service {
-	return Service{repo: repo, vfs: vfs, dbc: dbc}
+// hashNamespace returns namespace for vfsHashes, empty namespace is stored as DefaultNamespace.
+func hashNamespace(ns string) string {
+	if ns == NamespacePublic {
+		return DefaultNamespace
+	}
+	return ns
 }
 
 func (s Service) folderByID(ctx context.Context, id int) (*db.VfsFolder, error) {
@@ -73,6 +112,28 @@ func (s Service) GetFolder(ctx context.Context, rootFolderId int) (*Folder, erro
 	return NewFullFolder(dbf, childFolders), nil
 }
 
+// GetFolderTree returns Folder with nested sub folders down to depth level. Each folder has total files and files size.
+//
+//zenrpc:rootFolderId=1
+//zenrpc:depth=0 max sub folders level, 0 is unlimited
+//zenrpc:withDeleted=false include deleted folders
+//zenrpc:400 Invalid depth
+//zenrpc:404 Folder not found
+func (s Service) GetFolderTree(ctx context.Context, rootFolderId, depth int, withDeleted bool) (*Folder, error) {
+	if depth < 0 {
+		return nil, ErrInvalidInput
+	}
+
+	list, err := s.repo.FolderTree(ctx, rootFolderId, depth, withDeleted)
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if len(list) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return NewFolderTree(list), nil
+}
+
 // GetFolderBranch returns Folder branch.
 func (s Service) GetFolderBranch(ctx context.Context, folderId int) ([]Folder, error) {
 	dbf, err := s.folderByID(ctx, folderId)
@@ -100,7 +161,9 @@ func (s Service) GetFolderBranch(ctx context.Context, folderId int) ([]Folder, e
 //zenrpc:isDescending=true asc = false, desc = true
 //zenrpc:page=0 current page
 //zenrpc:pageSize=100 current pageSize
-func (s Service) GetFiles(ctx context.Context, folderId int, query *string, sortField string, isDescending bool, page, pageSize int) ([]File, error) {
+//zenrpc:tags files with all tags
+//zenrpc:400 Invalid tags
+func (s Service) GetFiles(ctx context.Context, folderId int, query *string, sortField string, isDescending bool, page, pageSize int, tags []string) ([]File, error) {
 	dbf, err := s.folderByID(ctx, folderId)
 	if err != nil {
 		return nil, err
@@ -110,32 +173,110 @@ func (s Service) GetFiles(ctx context.Context, folderId int, query *string, sort
 		return nil, ErrInvalidSort
 	}
 
+	tags, ok := normalizeTags(tags)
+	if !ok {
+		return nil, ErrInvalidInput
+	}
+
 	// set sort
 	sort := db.SortField{Column: sortField, Direction: db.SortAsc}
 	if isDescending {
 		sort.Direction = db.SortDesc
 	}
 
-	search := (&db.VfsFileSearch{FolderID: &dbf.ID}).WithQuery(query)
+	search := (&db.VfsFileSearch{FolderID: &dbf.ID}).WithQuery(query).WithTags(tags)
 	list, err := s.repo.VfsFilesByFilters(ctx, search, db.Pager{Page: page, PageSize: pageSize}, db.WithSort(sort))
 	if err != nil {
 		return nil, newInternalError(err)
 	}
 
-	files := make([]File, 0, len(list))
+	return s.newFiles(ctx, list)
+}
+
+// SearchFiles searches files across all folders or within folder subtree and returns found files with folder breadcrumbs and total count.
+//
+//zenrpc:search search filter
+//zenrpc:sortField="createdAt" createdAt, title or fileSize
+//zenrpc:isDescending=true asc = false, desc = true
+//zenrpc:page=0 current page
+//zenrpc:pageSize=100 current pageSize
+//zenrpc:400 Invalid filter ranges or page size
+//zenrpc:404 Root folder not found
+func (s Service) SearchFiles(ctx context.Context, search FileSearch, sortField string, isDescending bool, page, pageSize int) (*SearchFilesResponse, error) {
+	if sortField != "createdAt" && sortField != "title" && sortField != "fileSize" {
+		return nil, ErrInvalidSort
+	} else if !search.isValid() || pageSize < 1 || pageSize > maxSearchPageSize {
+		return nil, ErrInvalidInput
+	}
+
+	var ok bool
+	if search.Tags, ok = normalizeTags(search.Tags); !ok {
+		return nil, ErrInvalidInput
+	}
+
+	if search.RootFolderID != nil {
+		if _, err := s.folderByID(ctx, *search.RootFolderID); err != nil {
+			return nil, err
+		}
+	}
+
+	// set sort
+	sort := db.SortField{Column: sortField, Direction: db.SortAsc}
+	if isDescending {
+		sort.Direction = db.SortDesc
+	}
+
+	list, total, err := s.repo.SearchVfsFiles(ctx, search.ToDB(), db.Pager{Page: page, PageSize: pageSize}, db.WithSort(sort))
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	files, err := s.newFiles(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	// load folder branches once per folder
+	branches := make(map[int][]Folder)
+	resp := &SearchFilesResponse{Files: make([]FoundFile, 0, len(list)), Total: total}
 	for i := range list {
-		// nolint
-		files = append(files, *NewFile(&list[i], s.vfs.WebPath(""), s.vfs.PreviewPath(""))) // TODO ns?
+		folderID := list[i].FolderID
+		if _, ok := branches[folderID]; !ok {
+			fl, err := s.repo.FolderBranch(ctx, folderID)
+			if err != nil {
+				return nil, newInternalError(err)
+			}
+
+			branch := make([]Folder, 0, len(fl))
+			for j := range fl {
+				branch = append(branch, *NewFolder(&fl[j]))
+			}
+			branches[folderID] = branch
+		}
+
+		resp.Files = append(resp.Files, FoundFile{
+			File:        files[i],
+			FolderID:    folderID,
+			Breadcrumbs: branches[folderID],
+		})
 	}
-	return files, nil
+
+	return resp, nil
 }
 
 // CountFiles returns count of files.
 //
 //zenrpc:folderId root folder id
 //zenrpc:query file name
-func (s Service) CountFiles(ctx context.Context, folderId int, query *string) (int, error) {
-	search := (&db.VfsFileSearch{FolderID: &folderId}).WithQuery(query)
+//zenrpc:tags files with all tags
+//zenrpc:400 Invalid tags
+func (s Service) CountFiles(ctx context.Context, folderId int, query *string, tags []string) (int, error) {
+	tags, ok := normalizeTags(tags)
+	if !ok {
+		return 0, ErrInvalidInput
+	}
+
+	search := (&db.VfsFileSearch{FolderID: &folderId}).WithQuery(query).WithTags(tags)
 	count, err := s.repo.CountVfsFiles(ctx, search)
 	if err != nil {
 		return 0, newInternalError(err)
@@ -144,6 +285,29 @@ func (s Service) CountFiles(ctx context.Context, folderId int, query *string) (i
 	return count, nil
 }
 
+// newFiles converts db files to Files with tags.
+func (s Service) newFiles(ctx context.Context, list []db.VfsFile) ([]File, error) {
+	ids := make([]int, 0, len(list))
+	for i := range list {
+		ids = append(ids, list[i].ID)
+	}
+
+	tags, err := s.repo.FileTags(ctx, ids)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	files := make([]File, 0, len(list))
+	for i := range list {
+		// nolint
+		f := NewFile(&list[i], s.vfs.WebPath(""), s.vfs.PreviewPath("")) // TODO ns?
+		f.Tags = tags[list[i].ID]
+		files = append(files, *f)
+	}
+
+	return files, nil
+}
+
 // MoveFiles move files to destination folder.
 //
 //zenrpc:400 empty file ids
@@ -165,198 +329,685 @@ func (s Service) MoveFiles(ctx context.Context, fileIds []int64, destinationFold
 	return r, nil
 }
 
-// DeleteFiles remove files.
-func (s Service) DeleteFiles(ctx context.Context, fileIds []int64) (bool, error) {
-	if len(fileIds) == 0 {
-		return false, ErrInvalidInput
+// CopyFiles copies files into destination folder and returns new file ids in fileIds order.
+// Copies share physical files via hard links and keep title, params, metadata, texts and tags.
+//
+//zenrpc:400 Invalid file ids
+//zenrpc:404 Folder or file not found
+//zenrpc:409 File does not exist on disk
+//zenrpc:413 Folder quota exceeded
+func (s Service) CopyFiles(ctx context.Context, fileIds []int64, destinationFolderId int) ([]int, error) {
+	if len(fileIds) == 0 || len(fileIds) > maxBulkFiles {
+		return nil, ErrInvalidInput
 	}
 
-	r, err := s.repo.DeleteVfsFiles(ctx, fileIds)
+	fl, err := s.folderByID(ctx, destinationFolderId)
 	if err != nil {
-		return false, newInternalError(err)
+		return nil, err
 	}
 
-	return r, nil
-}
+	pos := make(map[int]int, len(fileIds))
+	for i, id := range fileIds {
+		if _, ok := pos[int(id)]; !ok {
+			pos[int(id)] = i
+		}
+	}
 
-// SetFilePhysicalName renames File on server.
-func (s Service) SetFilePhysicalName(ctx context.Context, fileId int, name string) (bool, error) {
-	if fileId == 0 || name == "" {
-		return false, ErrInvalidInput
+	ids := slices.Collect(maps.Keys(pos))
+	files, err := s.repo.VfsFilesByFilters(ctx, &db.VfsFileSearch{IDs: ids}, db.PagerNoLimit)
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if len(files) != len(ids) {
+		return nil, ErrNotFound
 	}
+	slices.SortFunc(files, func(a, b db.VfsFile) int { return pos[a.ID] - pos[b.ID] })
 
-	f, err := s.repo.VfsFileByID(ctx, fileId, db.EnabledOnly())
+	var paths []string
+	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		repo := s.repo.WithTransaction(tx)
+		if err = s.checkQuota(ctx, repo, fl.ID, files); err != nil {
+			return err
+		}
+
+		ids, paths, err = s.copyFiles(ctx, repo, files, fl.ID)
+		return err
+	})
 	if err != nil {
-		return false, newInternalError(err)
-	} else if f == nil {
-		return false, ErrNotFound
+		s.vfs.removeFiles(paths)
+		return nil, err
 	}
 
-	if !filenameRegex.MatchString(name) {
-		return false, newError(http.StatusNotAcceptable)
+	return ids, nil
+}
+
+// copyFiles adds copies of files into folder, physical files are shared via hard links within file namespace.
+// Returns new file ids and absolute paths of created links.
+func (s Service) copyFiles(ctx context.Context, repo db.VfsRepo, files []db.VfsFile, folderID int) (ids []int, paths []string, err error) {
+	srcIDs := make([]int, len(files))
+	for i := range files {
+		srcIDs[i] = files[i].ID
 	}
 
-	oldPath, newPath := f.Path, filepath.Join(filepath.Dir(f.Path), name)
-	if _, err = os.Stat(s.vfs.Path(NamespacePublic, newPath)); err == nil {
-		return false, newError(http.StatusConflict)
+	tags, err := repo.FileTags(ctx, srcIDs)
+	if err != nil {
+		return nil, nil, newInternalError(err)
 	}
 
-	// update path and move file in transaction
-	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) error {
-		txr := s.repo.WithTransaction(tx)
+	for _, f := range files {
+		ns, ok := s.vfs.fileNamespace(f.Path)
+		if !f.FileExists || !ok {
+			return ids, paths, zenrpc.NewStringError(http.StatusConflict, fmt.Sprintf("file %d does not exist on disk", f.ID))
+		}
 
-		f.Path = newPath
-		_, err = txr.UpdateVfsFile(ctx, f, db.WithColumns(db.Columns.VfsFile.Path))
+		id, err := repo.NextFileID()
 		if err != nil {
-			return err
+			return ids, paths, newInternalError(err)
 		}
 
-		return s.vfs.Move(NamespacePublic, oldPath, newPath)
-	})
+		p, dst, err := s.vfs.linkFile(ns, f.Path, folderID, id)
+		if err != nil {
+			return ids, paths, newInternalError(err)
+		}
+		paths = append(paths, dst)
 
-	if err != nil {
-		return false, newInternalError(err)
+		srcID := f.ID
+		f.ID, f.FolderID, f.Path, f.Folder = id, folderID, p, nil
+		f.StatusID, f.DeletedAt, f.CreatedAt = db.StatusEnabled, nil, time.Now()
+		if _, err = repo.AddVfsFile(ctx, &f); err != nil {
+			return ids, paths, newInternalError(err)
+		}
+
+		if t := tags[srcID]; len(t) > 0 {
+			if _, err = repo.AddFileTags(ctx, []int64{int64(id)}, t); err != nil {
+				return ids, paths, newInternalError(err)
+			}
+		}
+
+		ids = append(ids, id)
 	}
 
-	return true, nil
+	return ids, paths, nil
 }
 
-// SearchFolderByFileId return Folder by File id.
-func (s Service) SearchFolderByFileId(ctx context.Context, fileId int) (*Folder, error) {
-	if fileId == 0 {
-		return nil, ErrInvalidInput
+// checkQuota checks that files could be added into folder without exceeding folder quotas.
+// Repo must be in transaction with following files insert, see VFS.checkQuota.
+func (s Service) checkQuota(ctx context.Context, repo db.VfsRepo, folderID int, files []db.VfsFile) error {
+	var size int64
+	for _, f := range files {
+		if f.FileSize != nil {
+			size += int64(*f.FileSize)
+		}
 	}
 
-	f, err := s.repo.VfsFileByID(ctx, fileId, s.repo.FullVfsFile())
-	if err != nil {
-		return nil, newInternalError(err)
-	} else if f == nil {
-		return nil, ErrNotFound
+	err := s.vfs.checkQuota(ctx, repo, folderID, len(files), size)
+	if errors.Is(err, ErrQuotaExceeded) {
+		return zenrpc.NewStringError(http.StatusRequestEntityTooLarge, err.Error())
+	} else if err != nil {
+		return newInternalError(err)
 	}
 
-	return NewFolder(f.Folder), nil
+	return nil
 }
 
-// SearchFolderByFile return Folder by File name.
-func (s Service) SearchFolderByFile(ctx context.Context, filename string) (*Folder, error) {
-	if filename == "" {
-		return nil, ErrInvalidInput
+// DeleteFiles remove files.
+func (s Service) DeleteFiles(ctx context.Context, fileIds []int64) (bool, error) {
+	if len(fileIds) == 0 {
+		return false, ErrInvalidInput
 	}
 
-	list, fn := path.Split(filename)
-	searchPath := path.Join(path.Base(list), fn)
-
-	f, err := s.repo.OneVfsFile(ctx, &db.VfsFileSearch{Path: &searchPath}, s.repo.FullVfsFile())
+	r, err := s.repo.DeleteVfsFiles(ctx, fileIds)
 	if err != nil {
-		return nil, newInternalError(err)
-	} else if f == nil {
-		return nil, ErrNotFound
+		return false, newInternalError(err)
 	}
 
-	return NewFolder(f.Folder), nil
+	return r, nil
 }
 
-// GetFavorites return favorites list.
-func (s Service) GetFavorites(ctx context.Context) ([]Folder, error) {
-	b := true
-	list, err := s.repo.VfsFoldersByFilters(ctx, &db.VfsFolderSearch{IsFavorite: &b}, db.PagerNoLimit)
+// GetTags returns all tags with total tagged files.
+func (s Service) GetTags(ctx context.Context) ([]Tag, error) {
+	list, err := s.repo.Tags(ctx)
 	if err != nil {
 		return nil, newInternalError(err)
 	}
 
-	folders := make([]Folder, 0, len(list))
-	for i := range list {
-		folders = append(folders, *NewFolder(&list[i]))
+	tags := make([]Tag, 0, len(list))
+	for _, t := range list {
+		tags = append(tags, Tag{Title: t.Title, Files: t.Files})
 	}
-	return folders, nil
+
+	return tags, nil
 }
 
-// ManageFavorites manage favorite virtual folders.
-func (s Service) ManageFavorites(ctx context.Context, folderId int, isInFavorites bool) (bool, error) {
-	if folderId == 0 || folderId == 1 {
-		return false, ErrInvalidInput
+// AddFilesTags adds tags to files and returns total added file tags. Tags are trimmed and lowercased, new tags are created.
+//
+//zenrpc:400 Empty or too long file ids or invalid tags
+func (s Service) AddFilesTags(ctx context.Context, fileIds []int64, tags []string) (int, error) {
+	tags, ok := normalizeTags(tags)
+	if !ok || len(tags) == 0 || len(fileIds) == 0 || len(fileIds) > maxBulkFiles {
+		return 0, ErrInvalidInput
 	}
 
-	f, err := s.folderByID(ctx, folderId)
+	var count int
+	err := s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		count, err = s.repo.WithTransaction(tx).AddFileTags(ctx, fileIds, tags)
+		return err
+	})
 	if err != nil {
-		return false, newInternalError(err)
-	} else if f == nil {
-		return false, ErrNotFound
+		return 0, newInternalError(err)
 	}
 
-	f.IsFavorite = &isInFavorites
-	return s.repo.UpdateVfsFolder(ctx, f, db.WithColumns(db.Columns.VfsFolder.IsFavorite))
+	return count, nil
 }
 
-// CreateFolder create virtual folder.
-func (s Service) CreateFolder(ctx context.Context, rootFolderId int, name string) (bool, error) {
-	f, err := s.folderByID(ctx, rootFolderId)
-	if err != nil {
-		return false, err
+// RemoveFilesTags removes tags from files and returns total removed file tags.
+//
+//zenrpc:400 Empty or too long file ids or invalid tags
+func (s Service) RemoveFilesTags(ctx context.Context, fileIds []int64, tags []string) (int, error) {
+	tags, ok := normalizeTags(tags)
+	if !ok || len(tags) == 0 || len(fileIds) == 0 || len(fileIds) > maxBulkFiles {
+		return 0, ErrInvalidInput
 	}
 
-	if name == "" {
-		return false, ErrInvalidInput
+	count, err := s.repo.RemoveFileTags(ctx, fileIds, tags)
+	if err != nil {
+		return 0, newInternalError(err)
 	}
 
-	dbf := &db.VfsFolder{
-		ParentFolderID: &f.ID,
-		Title:          name,
-		CreatedAt:      time.Now(),
-		StatusID:       db.StatusEnabled,
-	}
+	return count, nil
+}
 
-	if _, err = s.repo.AddVfsFolder(ctx, dbf); err != nil {
-		return false, newInternalError(err)
+// SetFilesMeta merges key-value metadata into files metadata and returns total updated files. Existing keys are overwritten.
+//
+//zenrpc:meta metadata, e.g. {"alt": "Banner", "copyright": "ACME"}
+//zenrpc:400 Empty or too long file ids or invalid meta keys
+func (s Service) SetFilesMeta(ctx context.Context, fileIds []int64, meta map[string]string) (int, error) {
+	if len(meta) == 0 || !isValidMeta(meta) || len(fileIds) == 0 || len(fileIds) > maxBulkFiles {
+		return 0, ErrInvalidInput
 	}
 
-	return false, nil
-}
-
-// DeleteFolder removes Folder.
-func (s Service) DeleteFolder(ctx context.Context, folderId int) (bool, error) {
-	f, err := s.folderByID(ctx, folderId)
+	count, err := s.repo.SetFilesMeta(ctx, fileIds, meta)
 	if err != nil {
-		return false, err
+		return 0, newInternalError(err)
 	}
 
-	if folderId == 1 { // root
-		return false, ErrInvalidInput
+	return count, nil
+}
+
+// RemoveFilesMeta removes metadata keys from files and returns total updated files.
+//
+//zenrpc:400 Empty or too long file ids or empty keys
+func (s Service) RemoveFilesMeta(ctx context.Context, fileIds []int64, keys []string) (int, error) {
+	if len(keys) == 0 || len(fileIds) == 0 || len(fileIds) > maxBulkFiles {
+		return 0, ErrInvalidInput
 	}
 
-	if _, err = s.repo.DeleteVfsFolder(ctx, f.ID); err != nil {
-		return false, newInternalError(err)
+	count, err := s.repo.RemoveFilesMeta(ctx, fileIds, keys)
+	if err != nil {
+		return 0, newInternalError(err)
 	}
 
-	return true, nil
+	return count, nil
 }
 
-// MoveFolder move Folder to destination folder.
-func (s Service) MoveFolder(ctx context.Context, folderId, destinationFolderId int) (bool, error) {
-	if folderId == 1 || folderId == 0 || destinationFolderId == 0 || folderId == destinationFolderId {
+// SetFileTexts sets localized alt text, caption and copyright of file. Empty text removes language.
+//
+//zenrpc:fileId file id
+//zenrpc:lang language code, e.g. "en" or "pt-BR"
+//zenrpc:400 Invalid language or too long text
+//zenrpc:404 File not found
+//zenrpc:422 Alt text is required for published file
+func (s Service) SetFileTexts(ctx context.Context, fileId int, lang string, text FileText) (bool, error) {
+	if !langRegex.MatchString(lang) || !text.isValid() {
 		return false, ErrInvalidInput
 	}
 
-	// validate
-	fl, err := s.folderByID(ctx, folderId)
+	// only language is updated, concurrent updates of other languages are kept
+	var dbt *db.VfsFileText
+	if text = text.trim(); !text.isEmpty() {
+		t := text.ToDB()
+		dbt = &t
+	}
+
+	ok, err := s.repo.SetFileText(ctx, fileId, lang, dbt, s.vfs.cfg.RequireAltText)
 	if err != nil {
 		return false, newInternalError(err)
+	} else if ok {
+		return true, nil
 	}
 
-	dfl, err := s.folderByID(ctx, destinationFolderId)
+	// file is not found or alt text is required
+	f, err := s.repo.VfsFileByID(ctx, fileId, db.EnabledOnly())
 	if err != nil {
 		return false, newInternalError(err)
+	} else if f == nil {
+		return false, ErrNotFound
 	}
 
-	if fl == nil || dfl == nil {
-		return false, ErrNotFound
+	return false, ErrAltRequired
+}
+
+// PublishFiles marks files as published or unpublished and returns total updated files.
+// Files without alt text could not be published if VFS.RequireAltText is set.
+//
+//zenrpc:400 Empty or too long file ids
+//zenrpc:422 Alt text is required
+func (s Service) PublishFiles(ctx context.Context, fileIds []int64, isPublished bool) (int, error) {
+	if len(fileIds) == 0 || len(fileIds) > maxBulkFiles {
+		return 0, ErrInvalidInput
 	}
 
-	// check recursive path
-	if pathList, er := s.repo.FolderBranch(ctx, destinationFolderId); er != nil {
-		return false, newInternalError(er)
-	} else {
-		for i := len(pathList) - 1; i >= 0; i-- {
+	if isPublished && s.vfs.cfg.RequireAltText {
+		ids, err := s.repo.FilesWithoutAlt(ctx, fileIds)
+		if err != nil {
+			return 0, newInternalError(err)
+		} else if len(ids) > 0 {
+			return 0, zenrpc.NewStringError(http.StatusUnprocessableEntity, fmt.Sprintf("alt text is required for files: %v", ids))
+		}
+	}
+
+	count, err := s.repo.SetFilesPublished(ctx, fileIds, isPublished)
+	if err != nil {
+		return 0, newInternalError(err)
+	}
+
+	return count, nil
+}
+
+// GetFileVersions returns prior versions of file content, newest first.
+// File content is replaced by upload to /vfs/upload/file with fileId param.
+//
+//zenrpc:404 File not found
+func (s Service) GetFileVersions(ctx context.Context, fileId int) ([]FileVersion, error) {
+	f, err := s.repo.VfsFileByID(ctx, fileId)
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if f == nil {
+		return nil, ErrNotFound
+	}
+
+	list, err := s.repo.FileVersions(ctx, f.ID)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	versions := make([]FileVersion, 0, len(list))
+	for i := range list {
+		versions = append(versions, *NewFileVersion(&list[i], s.vfs.WebPath("")))
+	}
+
+	return versions, nil
+}
+
+// RollbackFile restores file content from prior version. Current content is kept as new version.
+//
+//zenrpc:fileId file id
+//zenrpc:versionId file version id
+//zenrpc:404 File or version not found
+//zenrpc:409 Version file does not exist on disk
+//zenrpc:413 Folder quota exceeded
+func (s Service) RollbackFile(ctx context.Context, fileId, versionId int) (bool, error) {
+	f, err := s.repo.VfsFileByID(ctx, fileId)
+	if err != nil {
+		return false, newInternalError(err)
+	} else if f == nil {
+		return false, ErrNotFound
+	}
+
+	versions, err := s.repo.FileVersions(ctx, f.ID)
+	if err != nil {
+		return false, newInternalError(err)
+	}
+
+	i := slices.IndexFunc(versions, func(v db.VfsFileVersion) bool { return v.ID == versionId })
+	if i == -1 {
+		return false, ErrNotFound
+	} else if !s.vfs.fileExists(versions[i].Path) {
+		return false, zenrpc.NewStringError(http.StatusConflict, fmt.Sprintf("version %d does not exist on disk", versionId))
+	}
+
+	var size int64
+	if v := versions[i]; v.FileSize != nil {
+		size = int64(*v.FileSize)
+	}
+	if f.FileSize != nil {
+		size -= int64(*f.FileSize)
+	}
+
+	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		repo := s.repo.WithTransaction(tx)
+		if err := s.vfs.checkQuota(ctx, repo, f.FolderID, 0, size); errors.Is(err, ErrQuotaExceeded) {
+			return zenrpc.NewStringError(http.StatusRequestEntityTooLarge, err.Error())
+		} else if err != nil {
+			return newInternalError(err)
+		}
+
+		if ok, err := repo.RollbackVfsFile(ctx, f.ID, versionId); err != nil {
+			return newInternalError(err)
+		} else if !ok {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SetFilePhysicalName renames File on server.
+func (s Service) SetFilePhysicalName(ctx context.Context, fileId int, name string) (bool, error) {
+	if fileId == 0 || name == "" {
+		return false, ErrInvalidInput
+	}
+
+	f, err := s.repo.VfsFileByID(ctx, fileId, db.EnabledOnly())
+	if err != nil {
+		return false, newInternalError(err)
+	} else if f == nil {
+		return false, ErrNotFound
+	}
+
+	if !filenameRegex.MatchString(name) {
+		return false, newError(http.StatusNotAcceptable)
+	}
+
+	oldPath, newPath := f.Path, filepath.Join(filepath.Dir(f.Path), name)
+	if _, err = os.Stat(s.vfs.Path(NamespacePublic, newPath)); err == nil {
+		return false, newError(http.StatusConflict)
+	}
+
+	// update path and move file in transaction
+	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		txr := s.repo.WithTransaction(tx)
+
+		f.Path = newPath
+		_, err = txr.UpdateVfsFile(ctx, f, db.WithColumns(db.Columns.VfsFile.Path))
+		if err != nil {
+			return err
+		}
+
+		return s.vfs.Move(NamespacePublic, oldPath, newPath)
+	})
+
+	if err != nil {
+		return false, newInternalError(err)
+	}
+
+	return true, nil
+}
+
+// SearchFolderByFileId return Folder by File id.
+func (s Service) SearchFolderByFileId(ctx context.Context, fileId int) (*Folder, error) {
+	if fileId == 0 {
+		return nil, ErrInvalidInput
+	}
+
+	f, err := s.repo.VfsFileByID(ctx, fileId, s.repo.FullVfsFile())
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if f == nil {
+		return nil, ErrNotFound
+	}
+
+	return NewFolder(f.Folder), nil
+}
+
+// SearchFolderByFile return Folder by File name.
+func (s Service) SearchFolderByFile(ctx context.Context, filename string) (*Folder, error) {
+	if filename == "" {
+		return nil, ErrInvalidInput
+	}
+
+	list, fn := path.Split(filename)
+	searchPath := path.Join(path.Base(list), fn)
+
+	f, err := s.repo.OneVfsFile(ctx, &db.VfsFileSearch{Path: &searchPath}, s.repo.FullVfsFile())
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if f == nil {
+		return nil, ErrNotFound
+	}
+
+	return NewFolder(f.Folder), nil
+}
+
+// GetFavorites return favorites list.
+func (s Service) GetFavorites(ctx context.Context) ([]Folder, error) {
+	b := true
+	list, err := s.repo.VfsFoldersByFilters(ctx, &db.VfsFolderSearch{IsFavorite: &b}, db.PagerNoLimit)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	folders := make([]Folder, 0, len(list))
+	for i := range list {
+		folders = append(folders, *NewFolder(&list[i]))
+	}
+	return folders, nil
+}
+
+// ManageFavorites manage favorite virtual folders.
+func (s Service) ManageFavorites(ctx context.Context, folderId int, isInFavorites bool) (bool, error) {
+	if folderId == 0 || folderId == 1 {
+		return false, ErrInvalidInput
+	}
+
+	f, err := s.folderByID(ctx, folderId)
+	if err != nil {
+		return false, newInternalError(err)
+	} else if f == nil {
+		return false, ErrNotFound
+	}
+
+	f.IsFavorite = &isInFavorites
+	return s.repo.UpdateVfsFolder(ctx, f, db.WithColumns(db.Columns.VfsFolder.IsFavorite))
+}
+
+// CreateFolder create virtual folder.
+func (s Service) CreateFolder(ctx context.Context, rootFolderId int, name string) (bool, error) {
+	f, err := s.folderByID(ctx, rootFolderId)
+	if err != nil {
+		return false, err
+	}
+
+	if name == "" {
+		return false, ErrInvalidInput
+	}
+
+	dbf := &db.VfsFolder{
+		ParentFolderID: &f.ID,
+		Title:          name,
+		CreatedAt:      time.Now(),
+		StatusID:       db.StatusEnabled,
+	}
+
+	if _, err = s.repo.AddVfsFolder(ctx, dbf); err != nil {
+		return false, newInternalError(err)
+	}
+
+	return false, nil
+}
+
+// DeleteFolder moves Folder to trash, see GetTrash. Not empty folder is deleted only in recursive mode with all sub folders and files.
+// Recursive deletion of more than VFS.ConfirmThreshold files requires confirm.
+//
+//zenrpc:recursive delete folder with sub folders and files, default is false
+//zenrpc:confirm confirm deletion of more than VFS.ConfirmThreshold files, default is false
+//zenrpc:400 Root folder
+//zenrpc:404 Folder not found
+//zenrpc:409 Folder is not empty
+//zenrpc:428 Confirmation is required
+func (s Service) DeleteFolder(ctx context.Context, folderId int, recursive, confirm *bool) (*DeleteFolderResponse, error) {
+	f, err := s.folderByID(ctx, folderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if folderId == 1 { // root
+		return nil, ErrInvalidInput
+	}
+
+	var resp DeleteFolderResponse
+	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		repo := s.repo.WithTransaction(tx)
+		if err = s.checkFolderTree(ctx, repo, f.ID, recursive != nil && *recursive, confirm != nil && *confirm); err != nil {
+			return err
+		}
+
+		resp.Folders, resp.Files, err = repo.DeleteFolderTree(ctx, f.ID, time.Now())
+		if err != nil {
+			return newInternalError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// checkFolderTree checks that folder is empty for not recursive operations
+// and confirm is set for recursive operations with more than VFS.ConfirmThreshold files.
+// It must be called with transaction repo: folder subtree stays locked until the end of transaction.
+func (s Service) checkFolderTree(ctx context.Context, repo db.VfsRepo, folderID int, recursive, confirm bool) error {
+	folders, files, err := repo.FolderTreeStats(ctx, folderID)
+	if err != nil {
+		return newInternalError(err)
+	}
+
+	if !recursive && (folders > 0 || files > 0) {
+		return zenrpc.NewStringError(http.StatusConflict, fmt.Sprintf("folder is not empty: %d folders, %d files", folders, files))
+	} else if files > s.vfs.ConfirmThreshold() && !confirm {
+		return zenrpc.NewStringError(http.StatusPreconditionRequired, fmt.Sprintf("confirm is required: %d folders, %d files", folders, files))
+	}
+
+	return nil
+}
+
+// GetTrash returns deleted folders and deleted files from not deleted folders, last deleted first.
+// Deleted items are removed after VFS.TrashRetention days.
+//
+//zenrpc:page=0 current page for files
+//zenrpc:pageSize=100 current pageSize for files
+func (s Service) GetTrash(ctx context.Context, page, pageSize int) (*TrashResponse, error) {
+	folders, err := s.repo.TrashFolders(ctx)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	list, total, err := s.repo.TrashFiles(ctx, db.Pager{Page: page, PageSize: pageSize})
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	files, err := s.newFiles(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	retention := s.vfs.TrashRetention()
+	resp := &TrashResponse{
+		Folders:    make([]TrashFolder, 0, len(folders)),
+		Files:      make([]TrashFile, 0, len(files)),
+		TotalFiles: total,
+	}
+	for _, f := range folders {
+		tf := TrashFolder{ID: f.ID, Name: f.Title, ParentID: f.ParentFolderID}
+		tf.DeletedAt, tf.PurgeAt = trashTimes(f.DeletedAt, retention)
+		resp.Folders = append(resp.Folders, tf)
+	}
+	for i := range list {
+		tf := TrashFile{File: files[i], FolderID: list[i].FolderID}
+		tf.DeletedAt, tf.PurgeAt = trashTimes(list[i].DeletedAt, retention)
+		resp.Files = append(resp.Files, tf)
+	}
+
+	return resp, nil
+}
+
+// RestoreFiles restores deleted files and returns total restored files. Files from deleted folders are not restored, use RestoreFolder.
+//
+//zenrpc:400 Empty or too long file ids
+func (s Service) RestoreFiles(ctx context.Context, fileIds []int64) (int, error) {
+	if len(fileIds) == 0 || len(fileIds) > maxBulkFiles {
+		return 0, ErrInvalidInput
+	}
+
+	count, err := s.repo.RestoreVfsFiles(ctx, fileIds)
+	if err != nil {
+		return 0, newInternalError(err)
+	}
+
+	return count, nil
+}
+
+// RestoreFolder restores deleted folder with sub folders and files deleted together with folder.
+//
+//zenrpc:404 Deleted folder not found
+//zenrpc:409 Parent folder is deleted
+func (s Service) RestoreFolder(ctx context.Context, folderId int) (*RestoreResponse, error) {
+	f, err := s.repo.DeletedVfsFolder(ctx, folderId)
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if f == nil {
+		return nil, ErrNotFound
+	}
+
+	if f.ParentFolderID != nil {
+		parent, err := s.repo.VfsFolderByID(ctx, *f.ParentFolderID)
+		if err != nil {
+			return nil, newInternalError(err)
+		} else if parent == nil {
+			return nil, newError(http.StatusConflict)
+		}
+	}
+
+	var resp RestoreResponse
+	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		resp.Folders, resp.Files, err = s.repo.WithTransaction(tx).RestoreFolderTree(ctx, f)
+		return err
+	})
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	return &resp, nil
+}
+
+// MoveFolder move Folder to destination folder. Moving folder with more than VFS.ConfirmThreshold files requires confirm.
+//
+//zenrpc:confirm=false confirm move of more than VFS.ConfirmThreshold files
+//zenrpc:409 Destination folder is in folder subtree
+//zenrpc:428 Confirmation is required
+func (s Service) MoveFolder(ctx context.Context, folderId, destinationFolderId int, confirm bool) (bool, error) {
+	if folderId == 1 || folderId == 0 || destinationFolderId == 0 || folderId == destinationFolderId {
+		return false, ErrInvalidInput
+	}
+
+	// validate
+	fl, err := s.folderByID(ctx, folderId)
+	if err != nil {
+		return false, newInternalError(err)
+	}
+
+	dfl, err := s.folderByID(ctx, destinationFolderId)
+	if err != nil {
+		return false, newInternalError(err)
+	}
+
+	if fl == nil || dfl == nil {
+		return false, ErrNotFound
+	}
+
+	// check recursive path
+	if pathList, er := s.repo.FolderBranch(ctx, destinationFolderId); er != nil {
+		return false, newInternalError(er)
+	} else {
+		for i := len(pathList) - 1; i >= 0; i-- {
 			p := pathList[i]
 			if p.ID == folderId {
 				return false, newError(http.StatusConflict)
@@ -365,15 +1016,135 @@ func (s Service) MoveFolder(ctx context.Context, folderId, destinationFolderId i
 	}
 
 	// move
-	fl.ParentFolderID = &dfl.ID
-	r, err := s.repo.UpdateVfsFolder(ctx, fl, db.WithColumns(db.Columns.VfsFolder.ParentFolderID))
+	var r bool
+	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) (err error) {
+		repo := s.repo.WithTransaction(tx)
+		if err = s.checkFolderTree(ctx, repo, fl.ID, true, confirm); err != nil {
+			return err
+		}
+
+		fl.ParentFolderID = &dfl.ID
+		if r, err = repo.UpdateVfsFolder(ctx, fl, db.WithColumns(db.Columns.VfsFolder.ParentFolderID)); err != nil {
+			return newInternalError(err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return false, newInternalError(err)
+		return false, err
 	}
 
 	return r, nil
 }
 
+// CopyFolder copies Folder with files into destination folder, sub folders are copied in recursive mode.
+// Copied files share physical files via hard links, see CopyFiles. Folder quotas are not copied.
+//
+//zenrpc:recursive=false copy sub folders with files
+//zenrpc:400 Root folder
+//zenrpc:404 Folder not found
+//zenrpc:409 Destination folder is in folder subtree or file does not exist on disk
+//zenrpc:413 Folder quota exceeded
+func (s Service) CopyFolder(ctx context.Context, folderId, destinationFolderId int, recursive bool) (*CopyFolderResponse, error) {
+	if folderId == 1 {
+		return nil, ErrInvalidInput
+	}
+
+	fl, err := s.folderByID(ctx, folderId)
+	if err != nil {
+		return nil, err
+	}
+
+	dfl, err := s.folderByID(ctx, destinationFolderId)
+	if err != nil {
+		return nil, err
+	}
+
+	// check recursive path
+	depth := 1
+	if recursive {
+		depth = 0
+		branch, err := s.repo.FolderBranch(ctx, dfl.ID)
+		if err != nil {
+			return nil, newInternalError(err)
+		} else if slices.ContainsFunc(branch, func(f db.VfsFolder) bool { return f.ID == fl.ID }) {
+			return nil, newError(http.StatusConflict)
+		}
+	}
+
+	// get folders and files
+	tree, err := s.repo.FolderTree(ctx, fl.ID, depth, false)
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if len(tree) == 0 {
+		return nil, ErrNotFound
+	} else if !recursive {
+		tree = tree[:1]
+	}
+
+	folderIDs := make([]int, len(tree))
+	for i := range tree {
+		folderIDs[i] = tree[i].ID
+	}
+
+	files, err := s.repo.VfsFilesByFilters(ctx, (&db.VfsFileSearch{}).WithFolderIDs(folderIDs), db.PagerNoLimit)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	folderFiles := make(map[int][]db.VfsFile, len(tree))
+	for _, f := range files {
+		folderFiles[f.FolderID] = append(folderFiles[f.FolderID], f)
+	}
+
+	// copy folders from top to bottom
+	var paths []string
+	resp := CopyFolderResponse{Folders: []int{}, Files: []int{}}
+	err = s.dbc.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		repo := s.repo.WithTransaction(tx)
+		if err := s.checkQuota(ctx, repo, dfl.ID, files); err != nil {
+			return err
+		}
+
+		// tree starts with copied folder, its copy is added into destination folder
+		newIDs := make(map[int]int, len(tree))
+		for i, it := range tree {
+			parentID := dfl.ID
+			if i > 0 && it.ParentFolderID != nil {
+				parentID = newIDs[*it.ParentFolderID]
+			}
+
+			nf, err := repo.AddVfsFolder(ctx, &db.VfsFolder{
+				ParentFolderID: &parentID,
+				Title:          it.Title,
+				CreatedAt:      time.Now(),
+				StatusID:       db.StatusEnabled,
+			})
+			if err != nil {
+				return newInternalError(err)
+			}
+			newIDs[it.ID] = nf.ID
+			resp.Folders = append(resp.Folders, nf.ID)
+
+			ids, pp, err := s.copyFiles(ctx, repo, folderFiles[it.ID], nf.ID)
+			paths = append(paths, pp...)
+			if err != nil {
+				return err
+			}
+			resp.Files = append(resp.Files, ids...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.vfs.removeFiles(paths)
+		return nil, err
+	}
+
+	resp.FolderID = resp.Folders[0]
+	return &resp, nil
+}
+
 // RenameFolder change Folder name.
 func (s Service) RenameFolder(ctx context.Context, folderId int, name string) (bool, error) {
 	if folderId == 0 || folderId == 1 || name == "" {
@@ -391,6 +1162,79 @@ func (s Service) RenameFolder(ctx context.Context, folderId int, name string) (b
 	return s.repo.UpdateVfsFolder(ctx, f, db.WithColumns(db.Columns.VfsFolder.Title))
 }
 
+// SetFolderQuota sets max total files size and files count in folder subtree, nil removes quota.
+// Uploads exceeding quota of folder or any parent folder are rejected with 413 error.
+// Quotas are not checked on moving and restoring files and folders, so usage could exceed quota after these operations.
+//
+//zenrpc:quotaBytes max total files size in bytes
+//zenrpc:quotaFiles max total files
+//zenrpc:400 Invalid quota
+//zenrpc:404 Folder not found
+func (s Service) SetFolderQuota(ctx context.Context, folderId int, quotaBytes *int64, quotaFiles *int) (bool, error) {
+	if quotaBytes != nil && *quotaBytes < 0 || quotaFiles != nil && *quotaFiles < 0 {
+		return false, ErrInvalidInput
+	}
+
+	f, err := s.folderByID(ctx, folderId)
+	if err != nil {
+		return false, err
+	}
+
+	f.QuotaBytes, f.QuotaFiles = quotaBytes, quotaFiles
+	ok, err := s.repo.UpdateVfsFolder(ctx, f, db.WithColumns(db.Columns.VfsFolder.QuotaBytes, db.Columns.VfsFolder.QuotaFiles))
+	if err != nil {
+		return false, newInternalError(err)
+	}
+
+	return ok, nil
+}
+
+// GetUsage returns total files and files size of folder subtree and each direct sub folder subtree with quotas
+// and total hashes and hashes size per namespace.
+//
+//zenrpc:rootFolderId=1
+//zenrpc:404 Folder not found
+func (s Service) GetUsage(ctx context.Context, rootFolderId int) (*UsageResponse, error) {
+	dbf, err := s.folderByID(ctx, rootFolderId)
+	if err != nil {
+		return nil, err
+	}
+
+	childFolders, err := s.repo.VfsFoldersByFilters(ctx, &db.VfsFolderSearch{ParentFolderID: &dbf.ID}, db.PagerNoLimit)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	folders := append([]db.VfsFolder{*dbf}, childFolders...)
+	ids := make([]int, len(folders))
+	for i := range folders {
+		ids[i] = folders[i].ID
+	}
+
+	usage, err := s.repo.FoldersUsage(ctx, ids)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	nsUsage, err := s.repo.NamespacesUsage(ctx)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	resp := &UsageResponse{
+		Folders:    make([]FolderUsage, len(folders)),
+		Namespaces: make([]NamespaceUsage, len(nsUsage)),
+	}
+	for i := range folders {
+		resp.Folders[i] = NewFolderUsage(folders[i], usage[folders[i].ID])
+	}
+	for i := range nsUsage {
+		resp.Namespaces[i] = NewNamespaceUsage(nsUsage[i])
+	}
+
+	return resp, nil
+}
+
 // HelpUpload returns a uploader help info.
 func (s Service) HelpUpload() HelpUploadResponse {
 	return HelpUploadResponse{
@@ -400,7 +1244,7 @@ func (s Service) HelpUpload() HelpUploadResponse {
 		},
 		Queue: HelpUploadItem{
 			URL:    "/vfs/upload/file",
-			Params: []string{s.vfs.cfg.UploadFormName, "folderId"},
+			Params: []string{s.vfs.cfg.UploadFormName, "folderId", "fileId"},
 		},
 	}
 }
@@ -468,3 +1312,247 @@ func (s Service) DeleteHash(ctx context.Context, namespace, hash string) (bool,
 
 	return true, nil
 }
+
+// GetHashColors returns dominant color and color palette of indexed image with its size and blurhash.
+//
+//zenrpc:namespace media namespace
+//zenrpc:hash media hash
+//zenrpc:404 Hash not found
+func (s Service) GetHashColors(ctx context.Context, namespace, hash string) (*HashColors, error) {
+	vfsHash, err := s.repo.VfsHashByID(ctx, hash, hashNamespace(namespace))
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if vfsHash == nil {
+		return nil, ErrNotFound
+	}
+
+	return NewHashColors(vfsHash), nil
+}
+
+// FindSimilar returns indexed images from namespace similar to hash by perceptual hash.
+//
+//zenrpc:namespace media namespace
+//zenrpc:hash media hash
+//zenrpc:distance=10 max hamming distance between perceptual hashes, from 0 to 64
+//zenrpc:400 Invalid distance
+//zenrpc:404 Hash not found or not indexed
+func (s Service) FindSimilar(ctx context.Context, namespace, hash string, distance int) ([]SimilarHash, error) {
+	if distance < 0 || distance > maxHashDistance {
+		return nil, ErrInvalidInput
+	}
+
+	vfsHash, err := s.repo.VfsHashByID(ctx, hash, hashNamespace(namespace))
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if vfsHash == nil || vfsHash.Phash == nil {
+		return nil, ErrNotFound
+	}
+
+	list, err := s.repo.SimilarHashes(ctx, vfsHash.Hash, vfsHash.Namespace, *vfsHash.Phash, distance, maxSimilarHashes)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	hashes := make([]SimilarHash, 0, len(list))
+	for i := range list {
+		hashes = append(hashes, *NewSimilarHash(&list[i], s.vfs.WebHashPath(namespace, NewFileHash(list[i].Hash, list[i].Extension))))
+	}
+	return hashes, nil
+}
+
+// GetHashInfo returns hash file info: extension, size, dimensions, blurhash and indexing status.
+//
+//zenrpc:namespace media namespace
+//zenrpc:hash media hash
+//zenrpc:404 Hash not found
+func (s Service) GetHashInfo(ctx context.Context, namespace, hash string) (*HashInfoResponse, error) {
+	vfsHash, err := s.repo.VfsHashByID(ctx, hash, hashNamespace(namespace))
+	if err != nil {
+		return nil, newInternalError(err)
+	} else if vfsHash == nil {
+		return nil, ErrNotFound
+	}
+
+	return NewHashInfoResponse(vfsHash), nil
+}
+
+// GetHashInfoList returns hash files info by hash list. Not found hashes are skipped.
+//
+//zenrpc:namespace media namespace
+//zenrpc:hashes media hash list, up to 1000 items
+//zenrpc:400 Empty or too long hash list
+func (s Service) GetHashInfoList(ctx context.Context, namespace string, hashes []string) ([]HashInfoResponse, error) {
+	if len(hashes) == 0 || len(hashes) > maxHashInfoList {
+		return nil, ErrInvalidInput
+	}
+
+	ns := hashNamespace(namespace)
+	list, err := s.repo.VfsHashesByFilters(ctx, &db.VfsHashSearch{Namespace: &ns, Hashes: hashes}, db.PagerNoLimit)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	resp := make([]HashInfoResponse, 0, len(list))
+	for i := range list {
+		resp = append(resp, *NewHashInfoResponse(&list[i]))
+	}
+	return resp, nil
+}
+
+// GetFailedHashes returns hashes from namespace that failed indexing after all attempts, recently failed first.
+//
+//zenrpc:namespace media namespace
+//zenrpc:page=0 current page
+//zenrpc:pageSize=100 current pageSize
+func (s Service) GetFailedHashes(ctx context.Context, namespace string, page, pageSize int) ([]HashInfoResponse, error) {
+	list, err := s.repo.FailedHashes(ctx, hashNamespace(namespace), db.Pager{Page: page, PageSize: pageSize})
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	resp := make([]HashInfoResponse, 0, len(list))
+	for i := range list {
+		resp = append(resp, *NewHashInfoResponse(&list[i]))
+	}
+	return resp, nil
+}
+
+// RequeueFailedHashes resets failed hashes for indexing and returns total requeued hashes.
+//
+//zenrpc:namespace media namespace
+//zenrpc:hashes media hash list, all failed hashes from namespace if empty
+//zenrpc:400 Too long hash list
+func (s Service) RequeueFailedHashes(ctx context.Context, namespace string, hashes []string) (int, error) {
+	if len(hashes) > maxHashInfoList {
+		return 0, ErrInvalidInput
+	}
+
+	count, err := s.repo.RequeueFailedHashes(ctx, hashNamespace(namespace), hashes)
+	if err != nil {
+		return 0, newInternalError(err)
+	}
+
+	return count, nil
+}
+
+// Reindex resets indexed hashes matched by filter into indexing queue and returns total queued hashes.
+//
+//zenrpc:filter reindex filter, all hashes are reindexed for empty filter
+//zenrpc:400 Invalid created at range
+func (s Service) Reindex(ctx context.Context, filter ReindexFilter) (int, error) {
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return 0, ErrInvalidInput
+	}
+
+	count, err := s.repo.ReindexHashes(ctx, filter.ToDB(), reindexBatchSize)
+	if err != nil {
+		return 0, newInternalError(err)
+	}
+
+	return count, nil
+}
+
+// IndexerStatus returns indexer progress: queue per namespace, throughput, ETA and scan status.
+//
+//zenrpc:503 Indexer is disabled
+func (s Service) IndexerStatus(ctx context.Context) (*IndexerStatus, error) {
+	if s.hi == nil {
+		return nil, ErrIndexerDisabled
+	}
+
+	st, err := s.hi.Status(ctx)
+	if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	return &st, nil
+}
+
+// NamespaceStats returns hashes count, size, average dimensions and indexed vs pending hashes per namespace and extension
+// with daily growth for last 30 days. Stats are refreshed in background, see UpdatedAt.
+//
+//zenrpc:503 Stats are not ready
+func (s Service) NamespaceStats(_ context.Context) (*NamespaceStatsResponse, error) {
+	if s.sr == nil {
+		return nil, ErrStatsNotReady
+	}
+
+	st := s.sr.Stats()
+	if st == nil {
+		return nil, ErrStatsNotReady
+	}
+
+	return st, nil
+}
+
+// StartScan starts files scan in background and returns scan job. Scan reads media folder and loads new files into indexing queue.
+//
+//zenrpc:namespaces namespaces for scan, all namespaces if empty
+//zenrpc:incremental=false skip hash directories not modified since last completed scan of namespace
+//zenrpc:files=false scan YYYYMM directories and update vfsFiles.fileExists instead of hashes
+//zenrpc:unsortedFolderId folder for unknown files found by files scan, files are not imported if empty
+//zenrpc:400 Invalid namespace or folder
+//zenrpc:409 Scan is already running
+//zenrpc:503 Indexer is disabled
+func (s Service) StartScan(_ context.Context, namespaces []string, incremental, files bool, unsortedFolderId *int) (*ScanJob, error) {
+	if s.hi == nil {
+		return nil, ErrIndexerDisabled
+	}
+
+	job, err := s.hi.StartScan(ScanOptions{Namespaces: namespaces, Incremental: incremental, Files: files, UnsortedFolderID: unsortedFolderId})
+	if errors.Is(err, ErrScanRunning) {
+		return nil, newError(http.StatusConflict)
+	} else if errors.Is(err, ErrInvalidNamespace) || errors.Is(err, ErrFolderNotFound) {
+		return nil, ErrInvalidInput
+	} else if err != nil {
+		return nil, newInternalError(err)
+	}
+
+	return &job, nil
+}
+
+// GetScanJob returns scan job with progress or results.
+//
+//zenrpc:id scan job id
+//zenrpc:404 Scan job not found
+//zenrpc:503 Indexer is disabled
+func (s Service) GetScanJob(_ context.Context, id string) (*ScanJob, error) {
+	if s.hi == nil {
+		return nil, ErrIndexerDisabled
+	}
+
+	job, ok := s.hi.ScanJob(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &job, nil
+}
+
+// GetScanJobs returns last scan jobs, newest first.
+//
+//zenrpc:503 Indexer is disabled
+func (s Service) GetScanJobs(_ context.Context) ([]ScanJob, error) {
+	if s.hi == nil {
+		return nil, ErrIndexerDisabled
+	}
+
+	return s.hi.ScanJobs(), nil
+}
+
+// CancelScan cancels running scan job.
+//
+//zenrpc:id scan job id
+//zenrpc:404 Running scan job not found
+//zenrpc:503 Indexer is disabled
+func (s Service) CancelScan(_ context.Context, id string) (bool, error) {
+	if s.hi == nil {
+		return false, ErrIndexerDisabled
+	}
+
+	if !s.hi.CancelScan(id) {
+		return false, ErrNotFound
+	}
+
+	return true, nil
+}
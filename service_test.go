@@ -2,6 +2,7 @@ package vfs_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"flag"
@@ -10,12 +11,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vmkteam/vfs"
 	"github.com/vmkteam/vfs/db"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/labstack/echo/v4"
 	"github.com/vmkteam/embedlog"
 )
 
@@ -24,6 +30,7 @@ var (
 	service  vfs.Service
 	testRepo db.VfsRepo
 	testVfs  vfs.VFS
+	testDB   *pg.DB
 )
 
 const testNs = "testns"
@@ -54,8 +61,9 @@ func TestMain(m *testing.M) {
 	testVfs = v
 
 	dbc := pg.Connect(cfg)
+	testDB = dbc
 	testRepo = db.NewVfsRepo(db.New(dbc))
-	service = vfs.NewService(testRepo, testVfs, dbc)
+	service = vfs.NewService(testRepo, testVfs, dbc, nil, nil)
 	os.Exit(m.Run())
 }
 
@@ -83,6 +91,16 @@ func TestDBService_GetFolder(t *testing.T) {
 	if string(d) != `[{"id":1,"name":"root","parentId":null},{"id":2,"name":"test","parentId":1},{"id":3,"name":"test2","parentId":2}]` {
 		t.Fatal(string(d))
 	}
+
+	// get tree
+	tree, err := service.GetFolderTree(ctx, 1, 2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Folders) != 1 || tree.Folders[0].ID != 2 || len(tree.Folders[0].Folders) != 1 || tree.Folders[0].Folders[0].ID != 3 || tree.Files == nil {
+		d, _ = json.Marshal(tree)
+		t.Fatal(string(d))
+	}
 }
 
 func TestDBService_GetFiles(t *testing.T) {
@@ -91,17 +109,396 @@ func TestDBService_GetFiles(t *testing.T) {
 
 	// get files
 	q := "photo"
-	files, err := service.GetFiles(ctx, 1, &q, "createdAt", true, 0, 100)
+	files, err := service.GetFiles(ctx, 1, &q, "createdAt", true, 0, 100, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	d, _ := json.Marshal(files)
-	if string(d) != `[{"id":9,"name":"photo_2019-07-30_14-18-07","path":"201908/1_9_ab990f98.jpg","relpath":"1_9_ab990f98","size":306926,"sizeH":["306.9","kB"],"date":"2019-08-05T12:24:13+00:00","type":"image/jpeg","extension":"jpg","params":{"width":1280,"height":960},"shortpath":"201908/1_9_ab990f98.jpg","width":1280,"height":960}]` {
+	if string(d) != `[{"id":9,"name":"photo_2019-07-30_14-18-07","path":"201908/1_9_ab990f98.jpg","relpath":"1_9_ab990f98","size":306926,"sizeH":["306.9","kB"],"date":"2019-08-05T12:24:13+00:00","type":"image/jpeg","extension":"jpg","params":{"width":1280,"height":960},"shortpath":"201908/1_9_ab990f98.jpg","width":1280,"height":960,"isPublished":false}]` {
 		t.Fatal(string(d))
 	}
 }
 
+func TestDBService_SearchFiles(t *testing.T) {
+	ctx := t.Context()
+
+	// search within subtree
+	rootID, minWidth := 1, 1
+	resp, err := service.SearchFiles(ctx, vfs.FileSearch{RootFolderID: &rootID, MinWidth: &minWidth}, "createdAt", true, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range resp.Files {
+		if len(f.Breadcrumbs) == 0 || f.Breadcrumbs[0].ID != rootID || f.Breadcrumbs[len(f.Breadcrumbs)-1].ID != f.FolderID {
+			t.Errorf("invalid breadcrumbs %+v for file %d", f.Breadcrumbs, f.File.ID)
+		}
+	}
+	if resp.Total < len(resp.Files) {
+		t.Errorf("total = %v, want >= %v", resp.Total, len(resp.Files))
+	}
+
+	// invalid input
+	minSize, maxSize := 10, 1
+	if _, err = service.SearchFiles(ctx, vfs.FileSearch{MinSize: &minSize, MaxSize: &maxSize}, "createdAt", true, 0, 100); err != vfs.ErrInvalidInput {
+		t.Errorf("SearchFiles() err = %v, want %v", err, vfs.ErrInvalidInput)
+	}
+	if _, err = service.SearchFiles(ctx, vfs.FileSearch{}, "path", true, 0, 100); err != vfs.ErrInvalidSort {
+		t.Errorf("SearchFiles() err = %v, want %v", err, vfs.ErrInvalidSort)
+	}
+}
+
+func TestDBService_FilesTags(t *testing.T) {
+	ctx := t.Context()
+
+	files, err := service.GetFiles(ctx, 1, nil, "createdAt", true, 0, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) == 0 {
+		t.Skip("no files")
+	}
+	fileIDs := []int64{int64(files[0].ID)}
+
+	// add tags and meta
+	if _, err = service.AddFilesTags(ctx, fileIDs, []string{"Test-Tag", "test-tag"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = service.SetFilesMeta(ctx, fileIDs, map[string]string{"alt": "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tagged, err := service.GetFiles(ctx, 1, nil, "createdAt", true, 0, 100, []string{"test-tag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tagged) != 1 || tagged[0].ID != files[0].ID || !slices.Equal(tagged[0].Tags, []string{"test-tag"}) || tagged[0].Meta["alt"] != "test" {
+		t.Errorf("GetFiles() = %+v", tagged)
+	}
+
+	// remove tags and meta
+	if n, err := service.RemoveFilesTags(ctx, fileIDs, []string{"test-tag"}); err != nil || n != 1 {
+		t.Errorf("RemoveFilesTags() = %v, %v", n, err)
+	}
+	if n, err := service.RemoveFilesMeta(ctx, fileIDs, []string{"alt"}); err != nil || n != 1 {
+		t.Errorf("RemoveFilesMeta() = %v, %v", n, err)
+	}
+
+	// invalid input
+	if _, err = service.AddFilesTags(ctx, nil, []string{"test-tag"}); err != vfs.ErrInvalidInput {
+		t.Errorf("AddFilesTags() err = %v, want %v", err, vfs.ErrInvalidInput)
+	}
+}
+
+func TestDBService_SetFileTexts(t *testing.T) {
+	ctx := t.Context()
+
+	fl, _ := testFolderTree(t, "texts")
+	f, err := testRepo.AddVfsFile(ctx, &db.VfsFile{FolderID: fl.ID, Title: "texts", Path: "texts.png", MimeType: "image/png", StatusID: db.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// set two languages and remove one
+	for lang, text := range map[string]vfs.FileText{"en": {Alt: "Banner"}, "de": {Alt: "Werbebanner"}} {
+		if _, err = service.SetFileTexts(ctx, f.ID, lang, text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = service.SetFileTexts(ctx, f.ID, "en", vfs.FileText{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := testRepo.VfsFileByID(ctx, f.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(got.Texts) != 1 || got.Texts["de"].Alt != "Werbebanner" {
+		t.Errorf("SetFileTexts() texts = %+v", got.Texts)
+	}
+
+	if _, err = service.SetFileTexts(ctx, 0, "en", vfs.FileText{Alt: "Banner"}); err != vfs.ErrNotFound {
+		t.Errorf("SetFileTexts() err = %v, want %v", err, vfs.ErrNotFound)
+	}
+}
+
+func TestDBService_FileVersions(t *testing.T) {
+	ctx := t.Context()
+
+	ts := httptest.NewServer(testVfs.UploadHandler(testRepo))
+	defer ts.Close()
+
+	// upload new file and replace it
+	fl, _ := testFolderTree(t, "versions")
+	created := testUploadFile(t, ts.URL, map[string]string{"folderId": strconv.Itoa(fl.ID), "ext": "png"})
+	if created.FileID == 0 {
+		t.Fatalf("upload failed: %+v", created)
+	}
+	replaced := testUploadFile(t, ts.URL, map[string]string{"fileId": strconv.Itoa(created.FileID), "ext": "png"})
+	if replaced.FileID != created.FileID {
+		t.Fatalf("replace failed: %+v", replaced)
+	}
+
+	versions, err := service.GetFileVersions(ctx, created.FileID)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(versions) != 1 {
+		t.Fatalf("GetFileVersions() = %+v, want 1 version", versions)
+	}
+
+	// rollback to first version
+	if _, err = service.RollbackFile(ctx, created.FileID, versions[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	rolled, err := service.GetFileVersions(ctx, created.FileID)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rolled) != 1 || rolled[0].ID == versions[0].ID || rolled[0].Path == versions[0].Path {
+		t.Errorf("GetFileVersions() after rollback = %+v", rolled)
+	}
+
+	if _, err = service.RollbackFile(ctx, created.FileID, versions[0].ID); err != vfs.ErrNotFound {
+		t.Errorf("RollbackFile() err = %v, want %v", err, vfs.ErrNotFound)
+	}
+
+	// rollback to version missing on disk
+	dbVersions, err := testRepo.FileVersions(ctx, created.FileID)
+	if err != nil || len(dbVersions) != 1 {
+		t.Fatalf("FileVersions() = %+v, err = %v", dbVersions, err)
+	}
+	if err = os.Remove(testVfs.Path(vfs.NamespacePublic, dbVersions[0].Path)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = service.RollbackFile(ctx, created.FileID, dbVersions[0].ID); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("RollbackFile() err = %v, want conflict", err)
+	}
+}
+
+func TestDBService_FolderQuota(t *testing.T) {
+	ctx := t.Context()
+
+	ts := httptest.NewServer(testVfs.UploadHandler(testRepo))
+	defer ts.Close()
+
+	// create folder with quota for single file
+	fl, _ := testFolderTree(t, "quota")
+	quotaFiles := 1
+	if _, err := service.SetFolderQuota(ctx, fl.ID, nil, &quotaFiles); err != nil {
+		t.Fatal(err)
+	}
+
+	folderID := strconv.Itoa(fl.ID)
+	if ur := testUploadFile(t, ts.URL, map[string]string{"folderId": folderID, "ext": "png"}); ur.FileID == 0 {
+		t.Fatalf("upload failed: %+v", ur)
+	}
+	if ur := testUploadFile(t, ts.URL, map[string]string{"folderId": folderID, "ext": "png"}); ur.FileID != 0 || !strings.Contains(ur.Error, vfs.ErrQuotaExceeded.Error()) {
+		t.Errorf("upload over quota = %+v, want %v", ur, vfs.ErrQuotaExceeded)
+	}
+
+	// check usage
+	usage, err := service.GetUsage(ctx, fl.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(usage.Folders) != 1 || usage.Folders[0].Files != 1 || usage.Folders[0].FilesSize == 0 {
+		t.Errorf("GetUsage() folders = %+v", usage.Folders)
+	}
+}
+
+func TestDBService_Copy(t *testing.T) {
+	ctx := t.Context()
+
+	ts := httptest.NewServer(testVfs.UploadHandler(testRepo))
+	defer ts.Close()
+
+	// create folder with sub folder and file
+	parent, child := testFolderTree(t, "copy")
+	ur := testUploadFile(t, ts.URL, map[string]string{"folderId": strconv.Itoa(child.ID), "ext": "png"})
+	if ur.FileID == 0 {
+		t.Fatalf("upload failed: %+v", ur)
+	}
+
+	// copy file and check hard link
+	ids, err := service.CopyFiles(ctx, []int64{int64(ur.FileID)}, parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(ids) != 1 || ids[0] == ur.FileID {
+		t.Fatalf("CopyFiles() = %v", ids)
+	}
+
+	src, err := testRepo.VfsFileByID(ctx, ur.FileID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp, err := testRepo.VfsFileByID(ctx, ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	srcInfo, err := os.Stat(testVfs.Path(vfs.NamespacePublic, src.Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpInfo, err := os.Stat(testVfs.Path(vfs.NamespacePublic, cp.Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.FolderID != parent.ID || cp.Path == src.Path || !os.SameFile(srcInfo, cpInfo) {
+		t.Errorf("CopyFiles() file = %+v, want hard link to %s", cp, src.Path)
+	}
+
+	// copy folder with sub folders
+	if _, err = service.CopyFolder(ctx, parent.ID, child.ID, true); err == nil {
+		t.Errorf("CopyFolder() err = nil, want conflict")
+	}
+
+	copied, err := service.CopyFolder(ctx, parent.ID, child.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(copied.Folders) != 1 || len(copied.Files) != 1 || copied.FolderID == parent.ID {
+		t.Errorf("CopyFolder() = %+v, want 1 folder and 1 file", copied)
+	}
+
+	copied, err = service.CopyFolder(ctx, child.ID, parent.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(copied.Folders) != 2 || len(copied.Files) != 2 {
+		t.Errorf("CopyFolder() = %+v, want 2 folders and 2 files", copied)
+	}
+
+	// copy file missing on disk
+	missing, err := testRepo.AddVfsFile(ctx, &db.VfsFile{FolderID: child.ID, Title: "missing", Path: "missing.png", MimeType: "image/png", StatusID: db.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = service.CopyFiles(ctx, []int64{int64(missing.ID)}, parent.ID); err == nil || !strings.Contains(err.Error(), strconv.Itoa(missing.ID)) {
+		t.Errorf("CopyFiles() err = %v, want conflict with file %d", err, missing.ID)
+	}
+}
+
+// testFolderTree creates folder with title under root folder and its sub folder.
+// Both folders with all sub folders and files are removed from DB after test.
+func testFolderTree(t *testing.T, title string) (parent, child *db.VfsFolder) {
+	t.Helper()
+	ctx := t.Context()
+
+	rootID := 1
+	parent, err := testRepo.AddVfsFolder(ctx, &db.VfsFolder{ParentFolderID: &rootID, Title: title, StatusID: db.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		tree, err := testRepo.FolderTree(ctx, parent.ID, 0, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([]int, len(tree))
+		for i := range tree {
+			ids[i] = tree[i].ID
+		}
+
+		for _, query := range []string{
+			`DELETE FROM "vfsFiles" WHERE "folderId" IN (?)`,
+			`UPDATE "vfsFolders" SET "parentFolderId" = NULL WHERE "folderId" IN (?)`,
+			`DELETE FROM "vfsFolders" WHERE "folderId" IN (?)`,
+		} {
+			if _, err = testDB.ExecContext(ctx, query, pg.In(ids)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	child, err = testRepo.AddVfsFolder(ctx, &db.VfsFolder{ParentFolderID: &parent.ID, Title: title + " child", StatusID: db.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parent, child
+}
+
+// testUploadFile uploads 1x1 png image with form fields.
+func testUploadFile(t *testing.T, url string, fields map[string]string) vfs.UploadResponse {
+	t.Helper()
+
+	data, err := base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNk+A8AAQUBAScY42YAAAAASUVORK5CYII=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := new(bytes.Buffer)
+	mp := multipart.NewWriter(body)
+	for k, v := range fields {
+		if err = mp.WriteField(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w, err := mp.CreateFormFile("Data", "test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	mp.Close()
+
+	res, err := http.Post(url, mp.FormDataContentType(), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var ur vfs.UploadResponse
+	if err = json.NewDecoder(res.Body).Decode(&ur); err != nil {
+		t.Fatal(err)
+	}
+
+	return ur
+}
+
+func TestDBService_Trash(t *testing.T) {
+	ctx := t.Context()
+
+	// create folder with sub folder and file
+	parent, child := testFolderTree(t, "trash")
+	_, err := testRepo.AddVfsFile(ctx, &db.VfsFile{FolderID: child.ID, Title: "trash", Path: "trash.png", MimeType: "image/png", StatusID: db.StatusEnabled})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// delete not empty folder without recursive
+	if _, err = service.DeleteFolder(ctx, parent.ID, nil, nil); err == nil {
+		t.Errorf("DeleteFolder() err = nil, want conflict")
+	}
+
+	// delete and check trash
+	recursive := true
+	deleted, err := service.DeleteFolder(ctx, parent.ID, &recursive, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if deleted.Folders != 2 || deleted.Files != 1 {
+		t.Errorf("DeleteFolder() = %+v, want 2 folders and 1 file", deleted)
+	}
+	trash, err := service.GetTrash(ctx, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(trash.Folders, func(f vfs.TrashFolder) bool { return f.ID == parent.ID }) ||
+		slices.ContainsFunc(trash.Folders, func(f vfs.TrashFolder) bool { return f.ID == child.ID }) {
+		t.Errorf("GetTrash() folders = %+v", trash.Folders)
+	}
+
+	// restore sub folder with deleted parent
+	if _, err = service.RestoreFolder(ctx, child.ID); err == nil {
+		t.Errorf("RestoreFolder() err = nil, want conflict")
+	}
+
+	restored, err := service.RestoreFolder(ctx, parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if restored.Folders != 2 || restored.Files != 1 {
+		t.Errorf("RestoreFolder() = %+v, want 2 folders and 1 file", restored)
+	}
+}
+
 func TestDBService_UrlByHash(t *testing.T) {
 	ctx := t.Context()
 
@@ -132,7 +529,7 @@ func TestDBService_UrlByHashList(t *testing.T) {
 func TestDBService_DeleteHash(t *testing.T) {
 	ctx := t.Context()
 
-	ts := httptest.NewServer(testVfs.HashUploadHandler(&testRepo))
+	ts := httptest.NewServer(testVfs.HashUploadHandler(&testRepo, nil))
 	defer ts.Close()
 
 	// hash for upload
@@ -186,3 +583,81 @@ func TestDBService_DeleteHash(t *testing.T) {
 		t.Fatalf("deleting existed hash err=%v", err)
 	}
 }
+
+func TestDBHashIndexer_PreviewReindexed(t *testing.T) {
+	ctx := t.Context()
+
+	// indexed hash with blurhash
+	indexedAt, ext, bh := time.Now().Add(-time.Minute), "prv", "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+	h, err := testRepo.AddVfsHash(ctx, &db.VfsHash{
+		Hash:      "preview" + strconv.FormatInt(indexedAt.UnixNano(), 10),
+		Namespace: testNs,
+		Extension: ext,
+		Width:     32,
+		Height:    32,
+		Blurhash:  &bh,
+		CreatedAt: indexedAt,
+		IndexedAt: &indexedAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = testDB.ExecContext(context.Background(), `DELETE FROM "vfsHashes" WHERE "hash" = ?`, h.Hash)
+	})
+
+	// reindex keeps blurhash and resets indexedAt
+	ns := testNs
+	if _, err = testRepo.ReindexHashes(ctx, db.ReindexFilter{Namespace: &ns, Extension: &ext}, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	hi := vfs.NewHashIndexer(embedlog.Logger{}, db.New(testDB), &testRepo, testVfs, 1, 10, false, nil)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-Modified-Since", time.Now().UTC().Format(http.TimeFormat))
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("ns", "file")
+	c.SetParamValues(testNs, h.Hash+".jpg")
+
+	if err = hi.Preview(c); err != nil {
+		t.Fatal(err)
+	} else if rec.Code != http.StatusNotFound {
+		t.Errorf("Preview() code = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestDBService_FindSimilar(t *testing.T) {
+	ctx := t.Context()
+
+	// hashes with perceptual hash distances 0, 3 and 64 from base
+	prefix := "similar" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	phashes := []int64{0, 0b1011, -1}
+	for i, ph := range phashes {
+		if _, err := testRepo.AddVfsHash(ctx, &db.VfsHash{
+			Hash:      prefix + strconv.Itoa(i),
+			Namespace: testNs,
+			Extension: "png",
+			CreatedAt: time.Now(),
+			Phash:     &ph,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		_, _ = testDB.ExecContext(context.Background(), `DELETE FROM "vfsHashes" WHERE "hash" LIKE ?`, prefix+"%")
+	})
+
+	similar, err := service.FindSimilar(ctx, testNs, prefix+"0", 10)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(similar) != 1 || similar[0].Hash != prefix+"1" || similar[0].Distance != 3 {
+		t.Errorf("FindSimilar() = %+v, want %s with distance 3", similar, prefix+"1")
+	}
+
+	if similar, err = service.FindSimilar(ctx, testNs, prefix+"0", 64); err != nil {
+		t.Fatal(err)
+	} else if len(similar) != 2 || similar[1].Hash != prefix+"2" || similar[1].Distance != 64 {
+		t.Errorf("FindSimilar() = %+v, want %s with distance 64 last", similar, prefix+"2")
+	}
+}
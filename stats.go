@@ -0,0 +1,202 @@
+package vfs
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+
+	"github.com/vmkteam/embedlog"
+)
+
+const (
+	defaultStatsInterval = 10 * time.Minute
+
+	// statsGrowthDays is total days for namespace growth stats.
+	statsGrowthDays = 30
+
+	statsDayLayout = "2006-01-02"
+)
+
+// ExtensionStats is a hashes stats for extension in namespace.
+type ExtensionStats struct {
+	Extension string  `json:"extension"`
+	Hashes    int     `json:"hashes"`
+	FilesSize int64   `json:"filesSize"`
+	AvgWidth  float64 `json:"avgWidth"`  // average width of hashes with dimensions
+	AvgHeight float64 `json:"avgHeight"` // average height of hashes with dimensions
+	Indexed   int     `json:"indexed"`
+	Pending   int     `json:"pending"`
+}
+
+// DailyGrowth is a total hashes and hashes size created in namespace per day.
+type DailyGrowth struct {
+	Date      string `json:"date"` // YYYY-MM-DD
+	Hashes    int    `json:"hashes"`
+	FilesSize int64  `json:"filesSize"`
+}
+
+// NamespaceStats is a hashes storage usage and growth for namespace.
+type NamespaceStats struct {
+	Namespace      string           `json:"namespace"`
+	Hashes         int              `json:"hashes"`
+	FilesSize      int64            `json:"filesSize"`
+	Indexed        int              `json:"indexed"`
+	Pending        int              `json:"pending"`
+	DailyHashes    float64          `json:"dailyHashes"`    // average created hashes per day for last 30 days
+	DailyFilesSize float64          `json:"dailyFilesSize"` // average created hashes size per day for last 30 days
+	Extensions     []ExtensionStats `json:"extensions"`
+	Growth         []DailyGrowth    `json:"growth"` // days with created hashes for last 30 days
+}
+
+// NamespaceStatsResponse is a namespaces stats with last refresh time.
+type NamespaceStatsResponse struct {
+	Namespaces []NamespaceStats `json:"namespaces"` // sorted by namespace
+	UpdatedAt  time.Time        `json:"updatedAt"`
+}
+
+// StatsRefresher periodically refreshes namespaces stats from vfsHashes, stats are not calculated per request.
+type StatsRefresher struct {
+	embedlog.Logger
+	repo       *db.VfsRepo
+	namespaces []string
+	interval   time.Duration
+
+	mu    sync.RWMutex
+	stats *NamespaceStatsResponse
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewStatsRefresher returns new stats refresher for VFS namespaces. Default interval is used for zero interval.
+func NewStatsRefresher(sl embedlog.Logger, v VFS, repo *db.VfsRepo, interval time.Duration) *StatsRefresher {
+	if interval <= 0 {
+		interval = defaultStatsInterval
+	}
+
+	return &StatsRefresher{
+		Logger:     sl,
+		repo:       repo,
+		namespaces: append([]string{DefaultNamespace}, v.cfg.Namespaces...),
+		interval:   interval,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start refreshes stats every interval until Stop is called.
+func (sr *StatsRefresher) Start() {
+	ctx := context.Background()
+	t := time.NewTicker(sr.interval)
+	defer t.Stop()
+
+	for {
+		if err := sr.Refresh(ctx, time.Now()); err != nil {
+			sr.Error(ctx, "namespace stats refresh failed", "err", err)
+		}
+
+		select {
+		case <-sr.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Stop stops refresher.
+func (sr *StatsRefresher) Stop() {
+	sr.stopOnce.Do(func() {
+		close(sr.stop)
+	})
+}
+
+// Refresh loads namespaces stats from DB.
+func (sr *StatsRefresher) Refresh(ctx context.Context, now time.Time) error {
+	exts, err := sr.repo.HashExtensionStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	from := now.AddDate(0, 0, -statsGrowthDays)
+	growth, err := sr.repo.HashDailyGrowth(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	st := &NamespaceStatsResponse{
+		Namespaces: newNamespaceStats(sr.namespaces, exts, growth),
+		UpdatedAt:  now,
+	}
+
+	sr.mu.Lock()
+	sr.stats = st
+	sr.mu.Unlock()
+
+	return nil
+}
+
+// Stats returns last refreshed stats, nil if stats were not refreshed yet.
+func (sr *StatsRefresher) Stats() *NamespaceStatsResponse {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	return sr.stats
+}
+
+// newNamespaceStats aggregates extension stats and daily growth by namespace.
+// Namespaces without hashes are returned with zero stats.
+func newNamespaceStats(namespaces []string, exts []db.HashExtensionStats, growth []db.HashDailyGrowth) []NamespaceStats {
+	idx := make(map[string]int)
+	var list []NamespaceStats
+	get := func(ns string) *NamespaceStats {
+		i, ok := idx[ns]
+		if !ok {
+			i = len(list)
+			idx[ns] = i
+			list = append(list, NamespaceStats{Namespace: ns, Extensions: []ExtensionStats{}, Growth: []DailyGrowth{}})
+		}
+
+		return &list[i]
+	}
+
+	for _, ns := range namespaces {
+		get(ns)
+	}
+
+	for _, e := range exts {
+		ns := get(e.Namespace)
+		ns.Hashes += e.Hashes
+		ns.FilesSize += e.FilesSize
+		ns.Indexed += e.Indexed
+		ns.Pending += e.Pending
+		ns.Extensions = append(ns.Extensions, ExtensionStats{
+			Extension: e.Extension,
+			Hashes:    e.Hashes,
+			FilesSize: e.FilesSize,
+			AvgWidth:  e.AvgWidth,
+			AvgHeight: e.AvgHeight,
+			Indexed:   e.Indexed,
+			Pending:   e.Pending,
+		})
+	}
+
+	for _, g := range growth {
+		ns := get(g.Namespace)
+		ns.DailyHashes += float64(g.Hashes) / statsGrowthDays
+		ns.DailyFilesSize += float64(g.FilesSize) / statsGrowthDays
+		ns.Growth = append(ns.Growth, DailyGrowth{
+			Date:      g.Day.Format(statsDayLayout),
+			Hashes:    g.Hashes,
+			FilesSize: g.FilesSize,
+		})
+	}
+
+	slices.SortFunc(list, func(a, b NamespaceStats) int {
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+
+	return list
+}
@@ -0,0 +1,43 @@
+package vfs
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+)
+
+func Test_newNamespaceStats(t *testing.T) {
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	exts := []db.HashExtensionStats{
+		{Namespace: "items", Extension: "jpg", Hashes: 10, FilesSize: 1000, AvgWidth: 800, AvgHeight: 600, Indexed: 8, Pending: 2},
+		{Namespace: "items", Extension: "png", Hashes: 5, FilesSize: 500, Indexed: 5},
+		{Namespace: "other", Extension: "pdf", Hashes: 1, FilesSize: 100, Pending: 1},
+	}
+	growth := []db.HashDailyGrowth{
+		{Namespace: "items", Day: day, Hashes: 30, FilesSize: 3000},
+	}
+
+	list := newNamespaceStats([]string{DefaultNamespace, "items", "test"}, exts, growth)
+
+	var names []string
+	for _, ns := range list {
+		names = append(names, ns.Namespace)
+	}
+	if want := []string{"default", "items", "other", "test"}; !slices.Equal(names, want) {
+		t.Fatalf("newNamespaceStats() namespaces = %v, want %v", names, want)
+	}
+
+	items := list[1]
+	if items.Hashes != 15 || items.FilesSize != 1500 || items.Indexed != 13 || items.Pending != 2 || len(items.Extensions) != 2 {
+		t.Errorf("newNamespaceStats() items = %+v", items)
+	}
+	if items.DailyHashes != 1 || items.DailyFilesSize != 100 || len(items.Growth) != 1 || items.Growth[0].Date != "2026-01-02" {
+		t.Errorf("newNamespaceStats() items growth = %+v, %v, %v", items.Growth, items.DailyHashes, items.DailyFilesSize)
+	}
+
+	if empty := list[3]; empty.Hashes != 0 || empty.Extensions == nil || empty.Growth == nil {
+		t.Errorf("newNamespaceStats() test = %+v", empty)
+	}
+}
@@ -0,0 +1,168 @@
+package vfs
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+)
+
+const (
+	// throughputWindow is total minutes for indexer throughput calculation.
+	throughputWindow = 5
+)
+
+// NamespaceQueue is indexing queue stats for namespace.
+type NamespaceQueue struct {
+	Namespace string `json:"namespace"`
+	Pending   int    `json:"pending"`  // hashes in queue, including retried
+	Retrying  int    `json:"retrying"` // hashes waiting for next attempt after transient error
+	Failed    int    `json:"failed"`   // hashes failed after all attempts
+}
+
+// IndexerStatus is a hash indexer progress and queue status.
+type IndexerStatus struct {
+	Namespaces []NamespaceQueue `json:"namespaces"` // ordered by IndexNamespacesPriority, then by name
+	Pending    int              `json:"pending"`
+	Failed     int              `json:"failed"`
+	Throughput float64          `json:"throughput"`    // indexed hashes per minute for last 5 minutes
+	ETA        *int             `json:"eta,omitempty"` // estimated seconds to process queue, empty if not calculated
+	Scanning   bool             `json:"scanning"`      // ScanFiles is running
+	LastScan   *ScanResults     `json:"lastScan,omitempty"`
+	LastScanAt *time.Time       `json:"lastScanAt,omitempty"`
+}
+
+// rateBucket is total indexed hashes per minute.
+type rateBucket struct {
+	minute int64
+	rows   int
+}
+
+// indexerStats is a shared indexer runtime stats.
+type indexerStats struct {
+	mu         sync.Mutex
+	buckets    [throughputWindow]rateBucket
+	lastScan   *ScanResults
+	lastScanAt *time.Time
+}
+
+// addRows adds indexed rows to throughput stats.
+func (s *indexerStats) addRows(rows int, now time.Time) {
+	if rows == 0 {
+		return
+	}
+
+	minute := now.Unix() / 60
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := &s.buckets[minute%throughputWindow]
+	if b.minute != minute {
+		b.minute, b.rows = minute, 0
+	}
+	b.rows += rows
+}
+
+// throughput returns indexed rows per minute for last throughputWindow minutes.
+func (s *indexerStats) throughput(now time.Time) float64 {
+	minute := now.Unix() / 60
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows int
+	for _, b := range s.buckets {
+		if minute-b.minute < throughputWindow {
+			rows += b.rows
+		}
+	}
+
+	return float64(rows) / throughputWindow
+}
+
+// setLastScan saves last successful scan results.
+func (s *indexerStats) setLastScan(r ScanResults, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastScan, s.lastScanAt = &r, &now
+}
+
+// scan returns last successful scan results.
+func (s *indexerStats) scan() (*ScanResults, *time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastScan, s.lastScanAt
+}
+
+// Status returns indexer queue status from db and runtime stats.
+func (hi HashIndexer) Status(ctx context.Context) (IndexerStatus, error) {
+	st := IndexerStatus{
+		Throughput: hi.stats.throughput(time.Now()),
+		Scanning:   hi.scanning.Load(),
+	}
+	st.LastScan, st.LastScanAt = hi.stats.scan()
+
+	if hi.repo == nil {
+		return st, nil
+	}
+
+	list, err := hi.repo.HashQueueStats(ctx)
+	if err != nil {
+		return st, err
+	}
+
+	st.Namespaces = newNamespaceQueues(list, append([]string{NamespacePublic}, hi.vfs.cfg.Namespaces...))
+	for _, q := range st.Namespaces {
+		st.Pending += q.Pending
+		st.Failed += q.Failed
+	}
+	sortNamespaceQueues(st.Namespaces, hi.nsPriority)
+
+	if st.Pending > 0 && st.Throughput > 0 {
+		eta := int(float64(st.Pending) / st.Throughput * 60)
+		st.ETA = &eta
+	}
+
+	return st, nil
+}
+
+// newNamespaceQueues returns queues from stats for all vfs namespaces, namespaces without stats have zero counts.
+// Namespaces from stats missing in vfs namespaces are kept.
+func newNamespaceQueues(list []db.HashQueueStats, namespaces []string) []NamespaceQueue {
+	queues := make([]NamespaceQueue, 0, len(namespaces)+len(list))
+	for _, ns := range namespaces {
+		queues = append(queues, NamespaceQueue{Namespace: hashNamespace(ns)})
+	}
+
+	for _, q := range list {
+		nq := NamespaceQueue{Namespace: q.Namespace, Pending: q.Pending, Retrying: q.Retrying, Failed: q.Failed}
+		if i := slices.IndexFunc(queues, func(e NamespaceQueue) bool { return e.Namespace == q.Namespace }); i >= 0 {
+			queues[i] = nq
+		} else {
+			queues = append(queues, nq)
+		}
+	}
+
+	return queues
+}
+
+// sortNamespaceQueues sorts queues in indexer order: namespaces from priority first, then others by name.
+func sortNamespaceQueues(list []NamespaceQueue, nsPriority []string) {
+	pos := func(ns string) int {
+		if i := slices.Index(nsPriority, ns); i >= 0 {
+			return i
+		}
+		return len(nsPriority)
+	}
+
+	slices.SortStableFunc(list, func(a, b NamespaceQueue) int {
+		if pa, pb := pos(a.Namespace), pos(b.Namespace); pa != pb {
+			return pa - pb
+		}
+		return strings.Compare(a.Namespace, b.Namespace)
+	})
+}
@@ -0,0 +1,54 @@
+package vfs
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+)
+
+func Test_indexerStats_throughput(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+
+	var s indexerStats
+	s.addRows(100, now.Add(-10*time.Minute)) // out of window
+	s.addRows(20, now.Add(-4*time.Minute))
+	s.addRows(10, now.Add(-time.Minute))
+	s.addRows(20, now)
+
+	if got, want := s.throughput(now), 10.0; got != want {
+		t.Errorf("throughput() = %v, want %v", got, want)
+	}
+
+	if got := s.throughput(now.Add(time.Hour)); got != 0 {
+		t.Errorf("throughput() = %v, want 0", got)
+	}
+}
+
+func Test_sortNamespaceQueues(t *testing.T) {
+	list := []NamespaceQueue{{Namespace: "a"}, {Namespace: "items"}, {Namespace: "default"}, {Namespace: "test"}}
+	sortNamespaceQueues(list, []string{"test", "items"})
+
+	want := []string{"test", "items", "a", "default"}
+	for i := range list {
+		if list[i].Namespace != want[i] {
+			t.Fatalf("sortNamespaceQueues() = %v, want %v", list, want)
+		}
+	}
+}
+
+func Test_newNamespaceQueues(t *testing.T) {
+	list := []db.HashQueueStats{{Namespace: "items", Pending: 2, Failed: 1}, {Namespace: "old", Pending: 1}}
+	got := newNamespaceQueues(list, []string{NamespacePublic, "items", "test"})
+
+	want := []NamespaceQueue{
+		{Namespace: DefaultNamespace},
+		{Namespace: "items", Pending: 2, Failed: 1},
+		{Namespace: "test"},
+		{Namespace: "old", Pending: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("newNamespaceQueues() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+
+	"github.com/vmkteam/embedlog"
+)
+
+const (
+	defaultTrashPurgeInterval = time.Hour
+
+	// trashPurgeBatchSize is total files removed by single query.
+	trashPurgeBatchSize = 1000
+)
+
+// TrashPurger periodically removes files and folders deleted more than retention ago from DB and disk.
+type TrashPurger struct {
+	embedlog.Logger
+	vfs       VFS
+	repo      *db.VfsRepo
+	retention time.Duration
+	interval  time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTrashPurger returns new purger with VFS.TrashRetention. Default interval is used for zero interval.
+func NewTrashPurger(sl embedlog.Logger, v VFS, repo *db.VfsRepo, interval time.Duration) *TrashPurger {
+	if interval <= 0 {
+		interval = defaultTrashPurgeInterval
+	}
+
+	return &TrashPurger{
+		Logger:    sl,
+		vfs:       v,
+		repo:      repo,
+		retention: v.TrashRetention(),
+		interval:  interval,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start purges trash every interval until Stop is called.
+func (tp *TrashPurger) Start() {
+	ctx := context.Background()
+	t := time.NewTicker(tp.interval)
+	defer t.Stop()
+
+	for {
+		files, folders, err := tp.Purge(ctx, time.Now())
+		if err != nil || files > 0 || folders > 0 {
+			tp.PrintOrErr(ctx, "trash purged", err, "files", files, "folders", folders)
+		}
+
+		select {
+		case <-tp.stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Stop stops purger.
+func (tp *TrashPurger) Stop() {
+	tp.stopOnce.Do(func() {
+		close(tp.stop)
+	})
+}
+
+// Purge removes files and folders deleted before now minus retention. Files are removed from disk after DB
+// from public namespace or namespace used for upload.
+// Returns total removed files without prior file versions and total removed folders.
+func (tp *TrashPurger) Purge(ctx context.Context, now time.Time) (files, folders int, err error) {
+	before := now.Add(-tp.retention)
+	for {
+		n, paths, err := tp.repo.PurgeFiles(ctx, before, trashPurgeBatchSize)
+		if err != nil {
+			return files, folders, err
+		}
+		files += n
+
+		var notFound int
+		for _, p := range paths {
+			ns, ok := tp.vfs.fileNamespace(p.Path)
+			if !ok {
+				notFound++
+				continue
+			}
+
+			if err := os.Remove(tp.vfs.Path(ns, p.Path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				tp.Error(ctx, "remove purged file failed", "err", err, "path", p.Path)
+			}
+		}
+		if notFound > 0 {
+			tp.Print(ctx, "purged files not found on disk", "files", notFound, "paths", len(paths))
+		}
+
+		if n < trashPurgeBatchSize {
+			break
+		}
+	}
+
+	folders, err = tp.repo.PurgeFolders(ctx, before)
+	return files, folders, err
+}
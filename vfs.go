@@ -31,7 +31,12 @@ import (
 )
 
 const (
+	// IndexSync is a value of "index" upload param for synchronous file indexing.
+	IndexSync = "sync"
+
 	DefaultHashExtension    = "jpg"
+	filesDirLayout          = "200601" // YYYYMM folder for vfsFiles
+	defaultConfirmThreshold = 100
 	NamespacePublic         = ""
 	defaultModePerm         = os.ModePerm
 	defaultHashFileModePerm = 0644
@@ -86,6 +91,15 @@ type Config struct {
 	UploadFormName   string
 	SaltedFilenames  bool
 	SkipFolderVerify bool
+
+	// RequireAltText forbids publishing files without alt text in any language.
+	RequireAltText bool
+
+	// ConfirmThreshold is a total files in folder subtree above which folder deletion or move requires confirmation, default is 100.
+	ConfirmThreshold int
+
+	// TrashRetention is a days count after which deleted files and folders are removed from DB and disk, 0 disables purge.
+	TrashRetention int
 }
 
 type VFS struct {
@@ -211,6 +225,20 @@ func (v VFS) HashUpload(r io.Reader, ns, ext string) (*FileHash, error) {
 	return &fh, nil
 }
 
+// ConfirmThreshold returns total files in folder subtree above which folder deletion or move requires confirmation.
+func (v VFS) ConfirmThreshold() int {
+	if v.cfg.ConfirmThreshold <= 0 {
+		return defaultConfirmThreshold
+	}
+
+	return v.cfg.ConfirmThreshold
+}
+
+// TrashRetention returns trash retention or 0 if purge is disabled.
+func (v VFS) TrashRetention() time.Duration {
+	return time.Duration(v.cfg.TrashRetention) * 24 * time.Hour
+}
+
 func (v VFS) Path(ns, path string) string {
 	return filepath.Join(v.cfg.Path, ns, path)
 }
@@ -290,13 +318,16 @@ func (v VFS) IsValidMimeType(mType string) bool {
 }
 
 type UploadResponse struct {
-	Code      int    `json:"-"`                 // http status code
-	Error     string `json:"error,omitempty"`   // error message
-	Hash      string `json:"hash,omitempty"`    // for hash
-	WebPath   string `json:"webPath,omitempty"` // for hash
-	FileID    int    `json:"id,omitempty"`      // vfs file id
-	Extension string `json:"ext,omitempty"`     // vfs file ext
-	Name      string `json:"name,omitempty"`    // vfs file name
+	Code      int    `json:"-"`                  // http status code
+	Error     string `json:"error,omitempty"`    // error message
+	Hash      string `json:"hash,omitempty"`     // for hash
+	WebPath   string `json:"webPath,omitempty"`  // for hash
+	FileID    int    `json:"id,omitempty"`       // vfs file id
+	Extension string `json:"ext,omitempty"`      // vfs file ext
+	Name      string `json:"name,omitempty"`     // vfs file name
+	Width     int    `json:"width,omitempty"`    // for hash with index=sync
+	Height    int    `json:"height,omitempty"`   // for hash with index=sync
+	Blurhash  string `json:"blurhash,omitempty"` // for hash with index=sync
 	Size      int64  `json:"-"`
 }
 
@@ -377,16 +408,17 @@ func (v VFS) uploadFile(r *http.Request, ns, ext, vfsFilename string) UploadResp
 	return UploadResponse{Code: http.StatusOK, Hash: fh.Hash, Extension: fh.Ext, WebPath: v.WebHashPath(ns, *fh), Size: fileSize}
 }
 
-func (v VFS) HashUploadHandler(repo *db.VfsRepo) http.HandlerFunc {
+// HashUploadHandler uploads hash file and saves it into vfsHashes if repo is set.
+// If "index" param is "sync" and indexer is set, file is indexed immediately and width, height & blurhash are returned.
+func (v VFS) HashUploadHandler(repo *db.VfsRepo, hi *HashIndexer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ns, ext := r.FormValue("ns"), strings.ToLower(r.FormValue("ext"))
 		ur := v.uploadFile(r, ns, ext, "")
+		if ns == "" {
+			ns = DefaultNamespace
+		}
 
 		if repo != nil && ur.Code == http.StatusOK {
-			if ns == "" {
-				ns = DefaultNamespace
-			}
-
 			if err := repo.SaveVfsHash(
 				r.Context(),
 				&db.VfsHash{Hash: ur.Hash, Namespace: ns, Extension: ur.Extension, FileSize: int(ur.Size), CreatedAt: time.Now()},
@@ -397,28 +429,71 @@ func (v VFS) HashUploadHandler(repo *db.VfsRepo) http.HandlerFunc {
 			}
 		}
 
+		// index file synchronously
+		if hi != nil && ur.Code == http.StatusOK && r.FormValue("index") == IndexSync {
+			h := &db.VfsHash{Hash: ur.Hash, Namespace: ns, Extension: ur.Extension}
+			if err := hi.IndexUploaded(r.Context(), repo, h); err != nil {
+				v.Error(r.Context(), "hash index failed", "err", err, "hash", ur.Hash)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			if h.Error == "" {
+				ur.Width, ur.Height = h.Width, h.Height
+				if h.Blurhash != nil {
+					ur.Blurhash = *h.Blurhash
+				}
+			} else {
+				v.Print(r.Context(), "hash index error", "err", h.Error, "hash", ur.Hash)
+			}
+		}
+
 		if err := v.writeHashUploadResponse(w, ur); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
 	}
 }
 
+// UploadHandler uploads file into folder (folderId param) or replaces content of existing file (fileId param).
+// Prior content of replaced file is kept as file version.
 func (v VFS) UploadHandler(repo db.VfsRepo) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ns, ext := r.FormValue("ns"), strings.ToLower(r.FormValue("ext"))
-		folderID, err := strconv.Atoi(r.FormValue("folderId"))
-		if err != nil {
-			http.Error(w, "bad folder "+err.Error(), http.StatusBadRequest)
-			return
-		}
 
-		fl, err := repo.VfsFolderByID(r.Context(), folderID)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		} else if fl == nil {
-			http.Error(w, "not found", http.StatusInternalServerError)
-			return
+		var (
+			fl *db.VfsFolder
+			vf *db.VfsFile
+		)
+		if s := r.FormValue("fileId"); s != "" {
+			fileID, err := strconv.Atoi(s)
+			if err != nil {
+				http.Error(w, "bad file "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			vf, err = repo.VfsFileByID(r.Context(), fileID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			} else if vf == nil {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			folderID, err := strconv.Atoi(r.FormValue("folderId"))
+			if err != nil {
+				http.Error(w, "bad folder "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			fl, err = repo.VfsFolderByID(r.Context(), folderID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			} else if fl == nil {
+				http.Error(w, "not found", http.StatusInternalServerError)
+				return
+			}
 		}
 
 		// generate temp filename
@@ -427,10 +502,22 @@ func (v VFS) UploadHandler(repo db.VfsRepo) http.HandlerFunc {
 		// upload file
 		ur := v.uploadFile(r, ns, ext, tempFile)
 		if ur.Code == http.StatusOK {
-			id, err := v.createFile(r.Context(), repo, fl, ns, tempFile, ur.Name, ur.Extension)
+			var (
+				id  int
+				err error
+			)
+			if vf != nil {
+				id, err = vf.ID, v.replaceFile(r.Context(), repo, vf, ns, tempFile, ur.Extension)
+			} else {
+				id, err = v.createFile(r.Context(), repo, fl, ns, tempFile, ur.Name, ur.Extension)
+			}
+
 			if err != nil {
 				ur.Error = err.Error()
 				ur.Code = http.StatusInternalServerError
+				if errors.Is(err, ErrQuotaExceeded) {
+					ur.Code = http.StatusRequestEntityTooLarge
+				}
 			} else {
 				ur.FileID = id
 			}
@@ -442,30 +529,21 @@ func (v VFS) UploadHandler(repo db.VfsRepo) http.HandlerFunc {
 	}
 }
 
-func (v VFS) createFile(ctx context.Context, repo db.VfsRepo, folder *db.VfsFolder, ns, relFilename, name, ext string) (int, error) {
-	var (
-		params *db.VfsFileParams
-		mType  string
-		fs     = 0
-	)
-	if reader, err := os.Open(v.Path(ns, relFilename)); err == nil {
-		// check for image
-		im, _, err := image.DecodeConfig(reader)
-		if err == nil {
-			params = &db.VfsFileParams{Height: im.Height, Width: im.Width}
-		} else {
-			log.Println(err)
-		}
-
-		// get file size
-		if fi, err := reader.Stat(); err == nil {
-			fs = int(fi.Size())
-		}
+func (v VFS) createFile(ctx context.Context, repo db.VfsRepo, folder *db.VfsFolder, ns, relFilename, name, ext string) (id int, err error) {
+	err = repo.RunInTransaction(ctx, func(repo db.VfsRepo) (err error) {
+		id, err = v.addFile(ctx, repo, folder, ns, relFilename, name, ext)
+		return err
+	})
 
-		// detect mime type
-		mType, _ = mimeType(reader)
+	return id, err
+}
 
-		reader.Close()
+// addFile checks folder quotas, moves uploaded file into YYYYMM folder and adds it to vfs.
+func (v VFS) addFile(ctx context.Context, repo db.VfsRepo, folder *db.VfsFolder, ns, relFilename, name, ext string) (int, error) {
+	params, mType, fs := v.fileInfo(ns, relFilename)
+	if err := v.checkQuota(ctx, repo, folder.ID, 1, int64(fs)); err != nil {
+		_ = os.Remove(v.Path(ns, relFilename))
+		return 0, err
 	}
 
 	// get last id
@@ -482,7 +560,7 @@ func (v VFS) createFile(ctx context.Context, repo db.VfsRepo, folder *db.VfsFold
 
 	// set newFilename
 	filename := fmt.Sprintf("%d_%d%s.%s", folder.ID, id, salt, ext) // like 1_9.png
-	curYearMonth := time.Now().Format("200601")
+	curYearMonth := time.Now().Format(filesDirLayout)
 
 	// move temp file to original path
 	err = v.Move(ns, relFilename, filepath.Join(curYearMonth, filename))
@@ -512,6 +590,139 @@ func (v VFS) createFile(ctx context.Context, repo db.VfsRepo, folder *db.VfsFold
 	return vf.ID, nil
 }
 
+// replaceFile moves uploaded file into YYYYMM folder and replaces file content, prior content is kept as file version.
+// New filename is always salted for cache busting.
+func (v VFS) replaceFile(ctx context.Context, repo db.VfsRepo, f *db.VfsFile, ns, relFilename, ext string) error {
+	params, mType, fs := v.fileInfo(ns, relFilename)
+
+	size := int64(fs)
+	if f.FileSize != nil {
+		size -= int64(*f.FileSize)
+	}
+	filename := fmt.Sprintf("%d_%d_%s.%s", f.FolderID, f.ID, randSeq(8), ext) // like 1_9_ab990f98.png
+	newPath := filepath.Join(time.Now().Format(filesDirLayout), filename)
+
+	return repo.RunInTransaction(ctx, func(repo db.VfsRepo) error {
+		if err := v.checkQuota(ctx, repo, f.FolderID, 0, size); err != nil {
+			_ = os.Remove(v.Path(ns, relFilename))
+			return err
+		}
+
+		if err := v.Move(ns, relFilename, newPath); err != nil {
+			return err
+		}
+
+		f.Path, f.Params, f.MimeType, f.FileSize = newPath, params, mType, &fs
+		ok, err := repo.ReplaceVfsFile(ctx, f)
+		if err == nil && !ok {
+			err = fmt.Errorf("file %d not found", f.ID)
+		}
+		if err != nil {
+			_ = os.Remove(v.Path(ns, newPath))
+			return err
+		}
+
+		return nil
+	})
+}
+
+// linkFile creates hard link to file in namespace as new file in YYYYMM folder of the same namespace, file content is not copied.
+// Returns new file path and absolute path of created link.
+func (v VFS) linkFile(ns, relPath string, folderID, fileID int) (string, string, error) {
+	salt := ""
+	if v.cfg.SaltedFilenames {
+		salt = "_" + randSeq(8)
+	}
+
+	filename := fmt.Sprintf("%d_%d%s%s", folderID, fileID, salt, filepath.Ext(relPath)) // like 1_9.png
+	newPath := filepath.Join(time.Now().Format(filesDirLayout), filename)
+	dst := v.Path(ns, newPath)
+	if err := os.MkdirAll(filepath.Dir(dst), defaultModePerm); err != nil {
+		return "", "", err
+	}
+
+	return newPath, dst, os.Link(v.Path(ns, relPath), dst)
+}
+
+// fileNamespace returns namespace of file from vfsFiles: files are uploaded into public namespace or namespace from upload request.
+func (v VFS) fileNamespace(relPath string) (string, bool) {
+	for _, ns := range append([]string{NamespacePublic}, v.cfg.Namespaces...) {
+		if _, err := os.Stat(v.Path(ns, relPath)); err == nil {
+			return ns, true
+		}
+	}
+
+	return NamespacePublic, false
+}
+
+// fileExists checks that file from vfsFiles exists in any namespace.
+func (v VFS) fileExists(relPath string) bool {
+	_, ok := v.fileNamespace(relPath)
+	return ok
+}
+
+// removeFiles removes files by absolute paths, errors are ignored.
+func (v VFS) removeFiles(paths []string) {
+	for _, p := range paths {
+		_ = os.Remove(p)
+	}
+}
+
+// importFile adds existing file from public namespace into vfsFiles without moving it.
+func (v VFS) importFile(ctx context.Context, repo db.VfsRepo, folderID int, relPath string) (int, error) {
+	params, mType, fs := v.fileInfo(NamespacePublic, relPath)
+
+	id, err := repo.NextFileID()
+	if err != nil {
+		return 0, err
+	}
+
+	vf, err := repo.AddVfsFile(ctx, &db.VfsFile{
+		ID:         id,
+		FolderID:   folderID,
+		Title:      filepath.Base(relPath),
+		Path:       relPath,
+		Params:     params,
+		MimeType:   mType,
+		FileSize:   &fs,
+		FileExists: true,
+		StatusID:   db.StatusEnabled,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return vf.ID, nil
+}
+
+// fileInfo returns image size, mime type and file size of file.
+func (v VFS) fileInfo(ns, relFilename string) (params *db.VfsFileParams, mType string, fs int) {
+	reader, err := os.Open(v.Path(ns, relFilename))
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+
+	// check for image
+	im, _, err := image.DecodeConfig(reader)
+	if err == nil {
+		params = &db.VfsFileParams{Height: im.Height, Width: im.Width}
+	} else {
+		log.Println(err)
+	}
+
+	// get file size
+	if fi, err := reader.Stat(); err == nil {
+		fs = int(fi.Size())
+	}
+
+	// detect mime type
+	mType, _ = mimeType(reader)
+
+	return
+}
+
 func randSeq(n int) string {
 	var letters = []rune("abcdefghijklmnopqrstuvwxyz0123456789")
 
@@ -11,15 +11,27 @@ import (
 )
 
 var RPC = struct {
-	Service struct{ GetFolder, GetFolderBranch, GetFiles, CountFiles, MoveFiles, DeleteFiles, SetFilePhysicalName, SearchFolderByFileId, SearchFolderByFile, GetFavorites, ManageFavorites, CreateFolder, DeleteFolder, MoveFolder, RenameFolder, HelpUpload, UrlByHash, UrlByHashList, DeleteHash string }
+	Service struct{ GetFolder, GetFolderTree, GetFolderBranch, GetFiles, SearchFiles, CountFiles, MoveFiles, CopyFiles, DeleteFiles, GetTags, AddFilesTags, RemoveFilesTags, SetFilesMeta, RemoveFilesMeta, SetFileTexts, PublishFiles, GetFileVersions, RollbackFile, SetFilePhysicalName, SearchFolderByFileId, SearchFolderByFile, GetFavorites, ManageFavorites, CreateFolder, DeleteFolder, GetTrash, RestoreFiles, RestoreFolder, MoveFolder, CopyFolder, RenameFolder, SetFolderQuota, GetUsage, HelpUpload, UrlByHash, UrlByHashList, DeleteHash, GetHashColors, FindSimilar, GetHashInfo, GetHashInfoList, GetFailedHashes, RequeueFailedHashes, Reindex, IndexerStatus, NamespaceStats, StartScan, GetScanJob, GetScanJobs, CancelScan string }
 }{
-	Service: struct{ GetFolder, GetFolderBranch, GetFiles, CountFiles, MoveFiles, DeleteFiles, SetFilePhysicalName, SearchFolderByFileId, SearchFolderByFile, GetFavorites, ManageFavorites, CreateFolder, DeleteFolder, MoveFolder, RenameFolder, HelpUpload, UrlByHash, UrlByHashList, DeleteHash string }{
+	Service: struct{ GetFolder, GetFolderTree, GetFolderBranch, GetFiles, SearchFiles, CountFiles, MoveFiles, CopyFiles, DeleteFiles, GetTags, AddFilesTags, RemoveFilesTags, SetFilesMeta, RemoveFilesMeta, SetFileTexts, PublishFiles, GetFileVersions, RollbackFile, SetFilePhysicalName, SearchFolderByFileId, SearchFolderByFile, GetFavorites, ManageFavorites, CreateFolder, DeleteFolder, GetTrash, RestoreFiles, RestoreFolder, MoveFolder, CopyFolder, RenameFolder, SetFolderQuota, GetUsage, HelpUpload, UrlByHash, UrlByHashList, DeleteHash, GetHashColors, FindSimilar, GetHashInfo, GetHashInfoList, GetFailedHashes, RequeueFailedHashes, Reindex, IndexerStatus, NamespaceStats, StartScan, GetScanJob, GetScanJobs, CancelScan string }{
 		GetFolder:            "getfolder",
+		GetFolderTree:        "getfoldertree",
 		GetFolderBranch:      "getfolderbranch",
 		GetFiles:             "getfiles",
+		SearchFiles:          "searchfiles",
 		CountFiles:           "countfiles",
 		MoveFiles:            "movefiles",
+		CopyFiles:            "copyfiles",
 		DeleteFiles:          "deletefiles",
+		GetTags:              "gettags",
+		AddFilesTags:         "addfilestags",
+		RemoveFilesTags:      "removefilestags",
+		SetFilesMeta:         "setfilesmeta",
+		RemoveFilesMeta:      "removefilesmeta",
+		SetFileTexts:         "setfiletexts",
+		PublishFiles:         "publishfiles",
+		GetFileVersions:      "getfileversions",
+		RollbackFile:         "rollbackfile",
 		SetFilePhysicalName:  "setfilephysicalname",
 		SearchFolderByFileId: "searchfolderbyfileid",
 		SearchFolderByFile:   "searchfolderbyfile",
@@ -27,12 +39,31 @@ var RPC = struct {
 		ManageFavorites:      "managefavorites",
 		CreateFolder:         "createfolder",
 		DeleteFolder:         "deletefolder",
+		GetTrash:             "gettrash",
+		RestoreFiles:         "restorefiles",
+		RestoreFolder:        "restorefolder",
 		MoveFolder:           "movefolder",
+		CopyFolder:           "copyfolder",
 		RenameFolder:         "renamefolder",
+		SetFolderQuota:       "setfolderquota",
+		GetUsage:             "getusage",
 		HelpUpload:           "helpupload",
 		UrlByHash:            "urlbyhash",
 		UrlByHashList:        "urlbyhashlist",
 		DeleteHash:           "deletehash",
+		GetHashColors:        "gethashcolors",
+		FindSimilar:          "findsimilar",
+		GetHashInfo:          "gethashinfo",
+		GetHashInfoList:      "gethashinfolist",
+		GetFailedHashes:      "getfailedhashes",
+		RequeueFailedHashes:  "requeuefailedhashes",
+		Reindex:              "reindex",
+		IndexerStatus:        "indexerstatus",
+		NamespaceStats:       "namespacestats",
+		StartScan:            "startscan",
+		GetScanJob:           "getscanjob",
+		GetScanJobs:          "getscanjobs",
+		CancelScan:           "cancelscan",
 	},
 }
 
@@ -73,6 +104,133 @@ func (Service) SMD() smd.ServiceInfo {
 								"$ref": "#/definitions/Folder",
 							},
 						},
+						{
+							Name:        "files",
+							Optional:    true,
+							Description: `total files in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "filesSize",
+							Optional:    true,
+							Description: `total files size in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name: "isDeleted",
+							Type: smd.Boolean,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"Folder": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:     "parentId",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "folders",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/Folder",
+									},
+								},
+								{
+									Name:        "files",
+									Optional:    true,
+									Description: `total files in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "filesSize",
+									Optional:    true,
+									Description: `total files size in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name: "isDeleted",
+									Type: smd.Boolean,
+								},
+							},
+						},
+					},
+				},
+				Errors: map[int]string{
+					404: "Folder not found",
+				},
+			},
+			"GetFolderTree": {
+				Description: `GetFolderTree returns Folder with nested sub folders down to depth level. Each folder has total files and files size.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:     "rootFolderId",
+						Optional: true,
+						Type:     smd.Integer,
+					},
+					{
+						Name:        "depth",
+						Optional:    true,
+						Description: `max sub folders level, 0 is unlimited`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "withDeleted",
+						Optional:    true,
+						Description: `include deleted folders`,
+						Type:        smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "Folder",
+					Properties: smd.PropertyList{
+						{
+							Name: "id",
+							Type: smd.Integer,
+						},
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name:     "parentId",
+							Optional: true,
+							Type:     smd.Integer,
+						},
+						{
+							Name: "folders",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/Folder",
+							},
+						},
+						{
+							Name:        "files",
+							Optional:    true,
+							Description: `total files in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "filesSize",
+							Optional:    true,
+							Description: `total files size in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name: "isDeleted",
+							Type: smd.Boolean,
+						},
 					},
 					Definitions: map[string]smd.Definition{
 						"Folder": {
@@ -98,11 +256,28 @@ func (Service) SMD() smd.ServiceInfo {
 										"$ref": "#/definitions/Folder",
 									},
 								},
+								{
+									Name:        "files",
+									Optional:    true,
+									Description: `total files in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "filesSize",
+									Optional:    true,
+									Description: `total files size in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name: "isDeleted",
+									Type: smd.Boolean,
+								},
 							},
 						},
 					},
 				},
 				Errors: map[int]string{
+					400: "Invalid depth",
 					404: "Folder not found",
 				},
 			},
@@ -144,6 +319,22 @@ func (Service) SMD() smd.ServiceInfo {
 										"$ref": "#/definitions/Folder",
 									},
 								},
+								{
+									Name:        "files",
+									Optional:    true,
+									Description: `total files in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "filesSize",
+									Optional:    true,
+									Description: `total files size in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name: "isDeleted",
+									Type: smd.Boolean,
+								},
 							},
 						},
 					},
@@ -187,6 +378,15 @@ func (Service) SMD() smd.ServiceInfo {
 						Description: `current pageSize`,
 						Type:        smd.Integer,
 					},
+					{
+						Name:        "tags",
+						Description: `files with all tags`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
 				},
 				Returns: smd.JSONSchema{
 					Type:     smd.Array,
@@ -260,6 +460,28 @@ func (Service) SMD() smd.ServiceInfo {
 									Optional: true,
 									Type:     smd.Integer,
 								},
+								{
+									Name: "tags",
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+								{
+									Name:        "meta",
+									Description: `custom metadata, e.g. source url`,
+									Type:        smd.Object,
+								},
+								{
+									Name:        "texts",
+									Description: `localized texts by language code`,
+									Ref:         "#/definitions/FileText",
+									Type:        smd.Object,
+								},
+								{
+									Name: "isPublished",
+									Type: smd.Boolean,
+								},
 							},
 						},
 						"FileParams": {
@@ -275,136 +497,174 @@ func (Service) SMD() smd.ServiceInfo {
 								},
 							},
 						},
-					},
-				},
-			},
-			"CountFiles": {
-				Description: `CountFiles returns count of files.`,
-				Parameters: []smd.JSONSchema{
-					{
-						Name:        "folderId",
-						Description: `root folder id`,
-						Type:        smd.Integer,
-					},
-					{
-						Name:        "query",
-						Optional:    true,
-						Description: `file name`,
-						Type:        smd.String,
-					},
-				},
-				Returns: smd.JSONSchema{
-					Type: smd.Integer,
-				},
-			},
-			"MoveFiles": {
-				Description: `MoveFiles move files to destination folder.`,
-				Parameters: []smd.JSONSchema{
-					{
-						Name:     "fileIds",
-						Type:     smd.Array,
-						TypeName: "[]",
-						Items: map[string]string{
-							"type": smd.Integer,
+						"FileText": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "alt",
+									Type: smd.String,
+								},
+								{
+									Name: "caption",
+									Type: smd.String,
+								},
+								{
+									Name: "copyright",
+									Type: smd.String,
+								},
+							},
 						},
 					},
-					{
-						Name: "destinationFolderId",
-						Type: smd.Integer,
-					},
-				},
-				Returns: smd.JSONSchema{
-					Type: smd.Boolean,
 				},
 				Errors: map[int]string{
-					400: "empty file ids",
+					400: "Invalid tags",
 				},
 			},
-			"DeleteFiles": {
-				Description: `DeleteFiles remove files.`,
+			"SearchFiles": {
+				Description: `SearchFiles searches files across all folders or within folder subtree and returns found files with folder breadcrumbs and total count.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:     "fileIds",
-						Type:     smd.Array,
-						TypeName: "[]",
-						Items: map[string]string{
-							"type": smd.Integer,
+						Name:        "search",
+						Description: `search filter`,
+						Type:        smd.Object,
+						TypeName:    "FileSearch",
+						Properties: smd.PropertyList{
+							{
+								Name:        "query",
+								Optional:    true,
+								Description: `title words or substring`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "rootFolderId",
+								Optional:    true,
+								Description: `search within folder and its sub folders`,
+								Type:        smd.Integer,
+							},
+							{
+								Name:        "mimeType",
+								Optional:    true,
+								Description: `exact mime type or prefix like "image/*"`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "extension",
+								Optional:    true,
+								Description: `file extension without dot`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "minSize",
+								Optional:    true,
+								Description: `min file size in bytes`,
+								Type:        smd.Integer,
+							},
+							{
+								Name:        "maxSize",
+								Optional:    true,
+								Description: `max file size in bytes`,
+								Type:        smd.Integer,
+							},
+							{
+								Name:        "createdFrom",
+								Optional:    true,
+								Description: `created at or after`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "createdTo",
+								Optional:    true,
+								Description: `created before`,
+								Type:        smd.String,
+							},
+							{
+								Name:     "minWidth",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "maxWidth",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "minHeight",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:     "maxHeight",
+								Optional: true,
+								Type:     smd.Integer,
+							},
+							{
+								Name:        "tags",
+								Description: `files with all tags`,
+								Type:        smd.Array,
+								Items: map[string]string{
+									"type": smd.String,
+								},
+							},
 						},
 					},
-				},
-				Returns: smd.JSONSchema{
-					Type: smd.Boolean,
-				},
-			},
-			"SetFilePhysicalName": {
-				Description: `SetFilePhysicalName renames File on server.`,
-				Parameters: []smd.JSONSchema{
 					{
-						Name: "fileId",
-						Type: smd.Integer,
+						Name:        "sortField",
+						Optional:    true,
+						Description: `createdAt, title or fileSize`,
+						Type:        smd.String,
 					},
 					{
-						Name: "name",
-						Type: smd.String,
+						Name:        "isDescending",
+						Optional:    true,
+						Description: `asc = false, desc = true`,
+						Type:        smd.Boolean,
 					},
-				},
-				Returns: smd.JSONSchema{
-					Type: smd.Boolean,
-				},
-			},
-			"SearchFolderByFileId": {
-				Description: `SearchFolderByFileId return Folder by File id.`,
-				Parameters: []smd.JSONSchema{
 					{
-						Name: "fileId",
-						Type: smd.Integer,
+						Name:        "page",
+						Optional:    true,
+						Description: `current page`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "pageSize",
+						Optional:    true,
+						Description: `current pageSize`,
+						Type:        smd.Integer,
 					},
 				},
 				Returns: smd.JSONSchema{
 					Optional: true,
 					Type:     smd.Object,
-					TypeName: "Folder",
+					TypeName: "SearchFilesResponse",
 					Properties: smd.PropertyList{
 						{
-							Name: "id",
-							Type: smd.Integer,
-						},
-						{
-							Name: "name",
-							Type: smd.String,
-						},
-						{
-							Name:     "parentId",
-							Optional: true,
-							Type:     smd.Integer,
-						},
-						{
-							Name: "folders",
+							Name: "files",
 							Type: smd.Array,
 							Items: map[string]string{
-								"$ref": "#/definitions/Folder",
+								"$ref": "#/definitions/FoundFile",
 							},
 						},
+						{
+							Name:        "total",
+							Description: `total found files`,
+							Type:        smd.Integer,
+						},
 					},
 					Definitions: map[string]smd.Definition{
-						"Folder": {
+						"FoundFile": {
 							Type: "object",
 							Properties: smd.PropertyList{
 								{
-									Name: "id",
-									Type: smd.Integer,
+									Name: "file",
+									Ref:  "#/definitions/File",
+									Type: smd.Object,
 								},
 								{
-									Name: "name",
-									Type: smd.String,
-								},
-								{
-									Name:     "parentId",
-									Optional: true,
-									Type:     smd.Integer,
+									Name: "folderId",
+									Type: smd.Integer,
 								},
 								{
-									Name: "folders",
+									Name: "breadcrumbs",
 									Type: smd.Array,
 									Items: map[string]string{
 										"$ref": "#/definitions/Folder",
@@ -412,45 +672,7 @@ func (Service) SMD() smd.ServiceInfo {
 								},
 							},
 						},
-					},
-				},
-			},
-			"SearchFolderByFile": {
-				Description: `SearchFolderByFile return Folder by File name.`,
-				Parameters: []smd.JSONSchema{
-					{
-						Name: "filename",
-						Type: smd.String,
-					},
-				},
-				Returns: smd.JSONSchema{
-					Optional: true,
-					Type:     smd.Object,
-					TypeName: "Folder",
-					Properties: smd.PropertyList{
-						{
-							Name: "id",
-							Type: smd.Integer,
-						},
-						{
-							Name: "name",
-							Type: smd.String,
-						},
-						{
-							Name:     "parentId",
-							Optional: true,
-							Type:     smd.Integer,
-						},
-						{
-							Name: "folders",
-							Type: smd.Array,
-							Items: map[string]string{
-								"$ref": "#/definitions/Folder",
-							},
-						},
-					},
-					Definitions: map[string]smd.Definition{
-						"Folder": {
+						"File": {
 							Type: "object",
 							Properties: smd.PropertyList{
 								{
@@ -462,32 +684,113 @@ func (Service) SMD() smd.ServiceInfo {
 									Type: smd.String,
 								},
 								{
-									Name:     "parentId",
+									Name: "path",
+									Type: smd.String,
+								},
+								{
+									Name: "previewpath",
+									Type: smd.String,
+								},
+								{
+									Name: "relpath",
+									Type: smd.String,
+								},
+								{
+									Name: "size",
+									Type: smd.Integer,
+								},
+								{
+									Name: "sizeH",
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+								{
+									Name: "date",
+									Type: smd.String,
+								},
+								{
+									Name: "type",
+									Type: smd.String,
+								},
+								{
+									Name: "extension",
+									Type: smd.String,
+								},
+								{
+									Name: "params",
+									Ref:  "#/definitions/FileParams",
+									Type: smd.Object,
+								},
+								{
+									Name: "shortpath",
+									Type: smd.String,
+								},
+								{
+									Name:     "width",
 									Optional: true,
 									Type:     smd.Integer,
 								},
 								{
-									Name: "folders",
+									Name:     "height",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "tags",
 									Type: smd.Array,
 									Items: map[string]string{
-										"$ref": "#/definitions/Folder",
+										"type": smd.String,
 									},
 								},
+								{
+									Name:        "meta",
+									Description: `custom metadata, e.g. source url`,
+									Type:        smd.Object,
+								},
+								{
+									Name:        "texts",
+									Description: `localized texts by language code`,
+									Ref:         "#/definitions/FileText",
+									Type:        smd.Object,
+								},
+								{
+									Name: "isPublished",
+									Type: smd.Boolean,
+								},
+							},
+						},
+						"FileParams": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "width",
+									Type: smd.Integer,
+								},
+								{
+									Name: "height",
+									Type: smd.Integer,
+								},
+							},
+						},
+						"FileText": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "alt",
+									Type: smd.String,
+								},
+								{
+									Name: "caption",
+									Type: smd.String,
+								},
+								{
+									Name: "copyright",
+									Type: smd.String,
+								},
 							},
 						},
-					},
-				},
-			},
-			"GetFavorites": {
-				Description: `GetFavorites return favorites list.`,
-				Parameters:  []smd.JSONSchema{},
-				Returns: smd.JSONSchema{
-					Type:     smd.Array,
-					TypeName: "[]Folder",
-					Items: map[string]string{
-						"$ref": "#/definitions/Folder",
-					},
-					Definitions: map[string]smd.Definition{
 						"Folder": {
 							Type: "object",
 							Properties: smd.PropertyList{
@@ -511,61 +814,96 @@ func (Service) SMD() smd.ServiceInfo {
 										"$ref": "#/definitions/Folder",
 									},
 								},
+								{
+									Name:        "files",
+									Optional:    true,
+									Description: `total files in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "filesSize",
+									Optional:    true,
+									Description: `total files size in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name: "isDeleted",
+									Type: smd.Boolean,
+								},
 							},
 						},
 					},
 				},
+				Errors: map[int]string{
+					400: "Invalid filter ranges or page size",
+					404: "Root folder not found",
+				},
 			},
-			"ManageFavorites": {
-				Description: `ManageFavorites manage favorite virtual folders.`,
+			"CountFiles": {
+				Description: `CountFiles returns count of files.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name: "folderId",
-						Type: smd.Integer,
-					},
-					{
-						Name: "isInFavorites",
-						Type: smd.Boolean,
+						Name:        "folderId",
+						Description: `root folder id`,
+						Type:        smd.Integer,
 					},
-				},
-				Returns: smd.JSONSchema{
-					Type: smd.Boolean,
-				},
-			},
-			"CreateFolder": {
-				Description: `CreateFolder create virtual folder.`,
-				Parameters: []smd.JSONSchema{
 					{
-						Name: "rootFolderId",
-						Type: smd.Integer,
+						Name:        "query",
+						Optional:    true,
+						Description: `file name`,
+						Type:        smd.String,
 					},
 					{
-						Name: "name",
-						Type: smd.String,
+						Name:        "tags",
+						Description: `files with all tags`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
 					},
 				},
 				Returns: smd.JSONSchema{
-					Type: smd.Boolean,
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Invalid tags",
 				},
 			},
-			"DeleteFolder": {
-				Description: `DeleteFolder removes Folder.`,
+			"MoveFiles": {
+				Description: `MoveFiles move files to destination folder.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name: "folderId",
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
+					},
+					{
+						Name: "destinationFolderId",
 						Type: smd.Integer,
 					},
 				},
 				Returns: smd.JSONSchema{
 					Type: smd.Boolean,
 				},
+				Errors: map[int]string{
+					400: "empty file ids",
+				},
 			},
-			"MoveFolder": {
-				Description: `MoveFolder move Folder to destination folder.`,
+			"CopyFiles": {
+				Description: `CopyFiles copies files into destination folder and returns new file ids in fileIds order.
+Copies share physical files via hard links and keep title, params, metadata, texts and tags.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name: "folderId",
-						Type: smd.Integer,
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
 					},
 					{
 						Name: "destinationFolderId",
@@ -573,170 +911,2923 @@ func (Service) SMD() smd.ServiceInfo {
 					},
 				},
 				Returns: smd.JSONSchema{
-					Type: smd.Boolean,
+					Type:     smd.Array,
+					TypeName: "[]",
+					Items: map[string]string{
+						"type": smd.Integer,
+					},
+				},
+				Errors: map[int]string{
+					400: "Invalid file ids",
+					404: "Folder or file not found",
+					409: "File does not exist on disk",
+					413: "Folder quota exceeded",
 				},
 			},
-			"RenameFolder": {
-				Description: `RenameFolder change Folder name.`,
+			"DeleteFiles": {
+				Description: `DeleteFiles remove files.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name: "folderId",
-						Type: smd.Integer,
-					},
-					{
-						Name: "name",
-						Type: smd.String,
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
 					},
 				},
 				Returns: smd.JSONSchema{
 					Type: smd.Boolean,
 				},
 			},
-			"HelpUpload": {
-				Description: `HelpUpload returns a uploader help info.`,
+			"GetTags": {
+				Description: `GetTags returns all tags with total tagged files.`,
 				Parameters:  []smd.JSONSchema{},
 				Returns: smd.JSONSchema{
-					Type:     smd.Object,
-					TypeName: "HelpUploadResponse",
-					Properties: smd.PropertyList{
-						{
-							Name: "temp",
-							Ref:  "#/definitions/HelpUploadItem",
-							Type: smd.Object,
-						},
-						{
-							Name: "queue",
-							Ref:  "#/definitions/HelpUploadItem",
-							Type: smd.Object,
-						},
+					Type:     smd.Array,
+					TypeName: "[]Tag",
+					Items: map[string]string{
+						"$ref": "#/definitions/Tag",
 					},
 					Definitions: map[string]smd.Definition{
-						"HelpUploadItem": {
+						"Tag": {
 							Type: "object",
 							Properties: smd.PropertyList{
 								{
-									Name: "url",
+									Name: "title",
 									Type: smd.String,
 								},
 								{
-									Name: "params",
-									Type: smd.Array,
-									Items: map[string]string{
-										"type": smd.String,
-									},
+									Name:        "files",
+									Description: `total tagged files`,
+									Type:        smd.Integer,
 								},
 							},
 						},
 					},
 				},
 			},
-			"UrlByHash": {
-				Description: `UrlByHash get Url by hash, namespace and media type`,
+			"AddFilesTags": {
+				Description: `AddFilesTags adds tags to files and returns total added file tags. Tags are trimmed and lowercased, new tags are created.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "hash",
-						Description: `media hash`,
-						Type:        smd.String,
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
 					},
 					{
-						Name:        "namespace",
-						Description: `media namespace`,
-						Type:        smd.String,
+						Name:     "tags",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Empty or too long file ids or invalid tags",
+				},
+			},
+			"RemoveFilesTags": {
+				Description: `RemoveFilesTags removes tags from files and returns total removed file tags.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
 					},
 					{
-						Name:        "mediaType",
-						Description: `type of media (possible values: small, medium, big, empty string)`,
-						Type:        smd.String,
+						Name:     "tags",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
 					},
 				},
 				Returns: smd.JSONSchema{
-					Type: smd.String,
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Empty or too long file ids or invalid tags",
 				},
 			},
-			"UrlByHashList": {
-				Description: `UrlByHashList get Urls by hash list, with namespace and media type`,
+			"SetFilesMeta": {
+				Description: `SetFilesMeta merges key-value metadata into files metadata and returns total updated files. Existing keys are overwritten.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "hashList",
-						Description: `media hash list`,
-						Type:        smd.Array,
-						TypeName:    "[]",
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
+					},
+					{
+						Name:        "meta",
+						Description: `metadata, e.g. {"alt": "Banner", "copyright": "ACME"}`,
+						Type:        smd.Object,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Empty or too long file ids or invalid meta keys",
+				},
+			},
+			"RemoveFilesMeta": {
+				Description: `RemoveFilesMeta removes metadata keys from files and returns total updated files.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
+					},
+					{
+						Name:     "keys",
+						Type:     smd.Array,
+						TypeName: "[]",
 						Items: map[string]string{
 							"type": smd.String,
 						},
 					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Empty or too long file ids or empty keys",
+				},
+			},
+			"SetFileTexts": {
+				Description: `SetFileTexts sets localized alt text, caption and copyright of file. Empty text removes language.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "fileId",
+						Description: `file id`,
+						Type:        smd.Integer,
+					},
 					{
-						Name:        "namespace",
-						Description: `media namespace`,
+						Name:        "lang",
+						Description: `language code, e.g. "en" or "pt-BR"`,
 						Type:        smd.String,
 					},
 					{
-						Name:        "mediaType",
-						Description: `type of media (possible values: small, medium, big, empty string)`,
-						Type:        smd.String,
+						Name:     "text",
+						Type:     smd.Object,
+						TypeName: "FileText",
+						Properties: smd.PropertyList{
+							{
+								Name: "alt",
+								Type: smd.String,
+							},
+							{
+								Name: "caption",
+								Type: smd.String,
+							},
+							{
+								Name: "copyright",
+								Type: smd.String,
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+				Errors: map[int]string{
+					400: "Invalid language or too long text",
+					404: "File not found",
+					422: "Alt text is required for published file",
+				},
+			},
+			"PublishFiles": {
+				Description: `PublishFiles marks files as published or unpublished and returns total updated files.
+Files without alt text could not be published if VFS.RequireAltText is set.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
+					},
+					{
+						Name: "isPublished",
+						Type: smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Empty or too long file ids",
+					422: "Alt text is required",
+				},
+			},
+			"GetFileVersions": {
+				Description: `GetFileVersions returns prior versions of file content, newest first.
+File content is replaced by upload to /vfs/upload/file with fileId param.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "fileId",
+						Type: smd.Integer,
 					},
 				},
 				Returns: smd.JSONSchema{
 					Type:     smd.Array,
-					TypeName: "[]UrlByHashListResponse",
+					TypeName: "[]FileVersion",
 					Items: map[string]string{
-						"$ref": "#/definitions/UrlByHashListResponse",
+						"$ref": "#/definitions/FileVersion",
 					},
 					Definitions: map[string]smd.Definition{
-						"UrlByHashListResponse": {
+						"FileVersion": {
 							Type: "object",
 							Properties: smd.PropertyList{
 								{
-									Name: "hash",
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "path",
 									Type: smd.String,
 								},
 								{
-									Name: "webPath",
+									Name: "size",
+									Type: smd.Integer,
+								},
+								{
+									Name: "type",
+									Type: smd.String,
+								},
+								{
+									Name:     "width",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name:     "height",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "replacedAt",
 									Type: smd.String,
 								},
 							},
 						},
 					},
 				},
+				Errors: map[int]string{
+					404: "File not found",
+				},
 			},
-			"DeleteHash": {
-				Description: `DeleteHash delete file by namespace and hash.`,
+			"RollbackFile": {
+				Description: `RollbackFile restores file content from prior version. Current content is kept as new version.`,
 				Parameters: []smd.JSONSchema{
 					{
-						Name:        "namespace",
-						Description: `media namespace`,
-						Type:        smd.String,
+						Name:        "fileId",
+						Description: `file id`,
+						Type:        smd.Integer,
 					},
 					{
-						Name:        "hash",
-						Description: `media hash`,
-						Type:        smd.String,
+						Name:        "versionId",
+						Description: `file version id`,
+						Type:        smd.Integer,
 					},
 				},
 				Returns: smd.JSONSchema{
 					Type: smd.Boolean,
 				},
 				Errors: map[int]string{
-					404: "File not found by hash",
+					404: "File or version not found",
+					409: "Version file does not exist on disk",
+					413: "Folder quota exceeded",
 				},
 			},
-		},
-	}
-}
+			"SetFilePhysicalName": {
+				Description: `SetFilePhysicalName renames File on server.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "fileId",
+						Type: smd.Integer,
+					},
+					{
+						Name: "name",
+						Type: smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+			},
+			"SearchFolderByFileId": {
+				Description: `SearchFolderByFileId return Folder by File id.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "fileId",
+						Type: smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "Folder",
+					Properties: smd.PropertyList{
+						{
+							Name: "id",
+							Type: smd.Integer,
+						},
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name:     "parentId",
+							Optional: true,
+							Type:     smd.Integer,
+						},
+						{
+							Name: "folders",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/Folder",
+							},
+						},
+						{
+							Name:        "files",
+							Optional:    true,
+							Description: `total files in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "filesSize",
+							Optional:    true,
+							Description: `total files size in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name: "isDeleted",
+							Type: smd.Boolean,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"Folder": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:     "parentId",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "folders",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/Folder",
+									},
+								},
+								{
+									Name:        "files",
+									Optional:    true,
+									Description: `total files in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "filesSize",
+									Optional:    true,
+									Description: `total files size in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name: "isDeleted",
+									Type: smd.Boolean,
+								},
+							},
+						},
+					},
+				},
+			},
+			"SearchFolderByFile": {
+				Description: `SearchFolderByFile return Folder by File name.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "filename",
+						Type: smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "Folder",
+					Properties: smd.PropertyList{
+						{
+							Name: "id",
+							Type: smd.Integer,
+						},
+						{
+							Name: "name",
+							Type: smd.String,
+						},
+						{
+							Name:     "parentId",
+							Optional: true,
+							Type:     smd.Integer,
+						},
+						{
+							Name: "folders",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/Folder",
+							},
+						},
+						{
+							Name:        "files",
+							Optional:    true,
+							Description: `total files in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "filesSize",
+							Optional:    true,
+							Description: `total files size in folder, only for folder tree`,
+							Type:        smd.Integer,
+						},
+						{
+							Name: "isDeleted",
+							Type: smd.Boolean,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"Folder": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:     "parentId",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "folders",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/Folder",
+									},
+								},
+								{
+									Name:        "files",
+									Optional:    true,
+									Description: `total files in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "filesSize",
+									Optional:    true,
+									Description: `total files size in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name: "isDeleted",
+									Type: smd.Boolean,
+								},
+							},
+						},
+					},
+				},
+			},
+			"GetFavorites": {
+				Description: `GetFavorites return favorites list.`,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Type:     smd.Array,
+					TypeName: "[]Folder",
+					Items: map[string]string{
+						"$ref": "#/definitions/Folder",
+					},
+					Definitions: map[string]smd.Definition{
+						"Folder": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:     "parentId",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "folders",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/Folder",
+									},
+								},
+								{
+									Name:        "files",
+									Optional:    true,
+									Description: `total files in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "filesSize",
+									Optional:    true,
+									Description: `total files size in folder, only for folder tree`,
+									Type:        smd.Integer,
+								},
+								{
+									Name: "isDeleted",
+									Type: smd.Boolean,
+								},
+							},
+						},
+					},
+				},
+			},
+			"ManageFavorites": {
+				Description: `ManageFavorites manage favorite virtual folders.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "folderId",
+						Type: smd.Integer,
+					},
+					{
+						Name: "isInFavorites",
+						Type: smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+			},
+			"CreateFolder": {
+				Description: `CreateFolder create virtual folder.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "rootFolderId",
+						Type: smd.Integer,
+					},
+					{
+						Name: "name",
+						Type: smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+			},
+			"DeleteFolder": {
+				Description: `DeleteFolder moves Folder to trash, see GetTrash. Not empty folder is deleted only in recursive mode with all sub folders and files.
+Recursive deletion of more than VFS.ConfirmThreshold files requires confirm.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "folderId",
+						Type: smd.Integer,
+					},
+					{
+						Name:        "recursive",
+						Optional:    true,
+						Description: `delete folder with sub folders and files, default is false`,
+						Type:        smd.Boolean,
+					},
+					{
+						Name:        "confirm",
+						Optional:    true,
+						Description: `confirm deletion of more than VFS.ConfirmThreshold files, default is false`,
+						Type:        smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "DeleteFolderResponse",
+					Properties: smd.PropertyList{
+						{
+							Name:        "folders",
+							Description: `deleted folders including folder itself`,
+							Type:        smd.Integer,
+						},
+						{
+							Name: "files",
+							Type: smd.Integer,
+						},
+					},
+				},
+				Errors: map[int]string{
+					400: "Root folder",
+					404: "Folder not found",
+					409: "Folder is not empty",
+					428: "Confirmation is required",
+				},
+			},
+			"GetTrash": {
+				Description: `GetTrash returns deleted folders and deleted files from not deleted folders, last deleted first.
+Deleted items are removed after VFS.TrashRetention days.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "page",
+						Optional:    true,
+						Description: `current page for files`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "pageSize",
+						Optional:    true,
+						Description: `current pageSize for files`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "TrashResponse",
+					Properties: smd.PropertyList{
+						{
+							Name:        "folders",
+							Description: `deleted folders with not deleted parent folder`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/TrashFolder",
+							},
+						},
+						{
+							Name:        "files",
+							Description: `deleted files from not deleted folders`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/TrashFile",
+							},
+						},
+						{
+							Name:        "totalFiles",
+							Description: `total deleted files from not deleted folders`,
+							Type:        smd.Integer,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"TrashFolder": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name:     "parentId",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "deletedAt",
+									Type: smd.String,
+								},
+								{
+									Name:        "purgeAt",
+									Optional:    true,
+									Description: `time after which folder is removed, empty if purge is disabled`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"TrashFile": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "file",
+									Ref:  "#/definitions/File",
+									Type: smd.Object,
+								},
+								{
+									Name: "folderId",
+									Type: smd.Integer,
+								},
+								{
+									Name: "deletedAt",
+									Type: smd.String,
+								},
+								{
+									Name:        "purgeAt",
+									Optional:    true,
+									Description: `time after which file is removed, empty if purge is disabled`,
+									Type:        smd.String,
+								},
+							},
+						},
+						"File": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "path",
+									Type: smd.String,
+								},
+								{
+									Name: "previewpath",
+									Type: smd.String,
+								},
+								{
+									Name: "relpath",
+									Type: smd.String,
+								},
+								{
+									Name: "size",
+									Type: smd.Integer,
+								},
+								{
+									Name: "sizeH",
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+								{
+									Name: "date",
+									Type: smd.String,
+								},
+								{
+									Name: "type",
+									Type: smd.String,
+								},
+								{
+									Name: "extension",
+									Type: smd.String,
+								},
+								{
+									Name: "params",
+									Ref:  "#/definitions/FileParams",
+									Type: smd.Object,
+								},
+								{
+									Name: "shortpath",
+									Type: smd.String,
+								},
+								{
+									Name:     "width",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name:     "height",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name: "tags",
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+								{
+									Name:        "meta",
+									Description: `custom metadata, e.g. source url`,
+									Type:        smd.Object,
+								},
+								{
+									Name:        "texts",
+									Description: `localized texts by language code`,
+									Ref:         "#/definitions/FileText",
+									Type:        smd.Object,
+								},
+								{
+									Name: "isPublished",
+									Type: smd.Boolean,
+								},
+							},
+						},
+						"FileParams": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "width",
+									Type: smd.Integer,
+								},
+								{
+									Name: "height",
+									Type: smd.Integer,
+								},
+							},
+						},
+						"FileText": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "alt",
+									Type: smd.String,
+								},
+								{
+									Name: "caption",
+									Type: smd.String,
+								},
+								{
+									Name: "copyright",
+									Type: smd.String,
+								},
+							},
+						},
+					},
+				},
+			},
+			"RestoreFiles": {
+				Description: `RestoreFiles restores deleted files and returns total restored files. Files from deleted folders are not restored, use RestoreFolder.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:     "fileIds",
+						Type:     smd.Array,
+						TypeName: "[]",
+						Items: map[string]string{
+							"type": smd.Integer,
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Empty or too long file ids",
+				},
+			},
+			"RestoreFolder": {
+				Description: `RestoreFolder restores deleted folder with sub folders and files deleted together with folder.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "folderId",
+						Type: smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "RestoreResponse",
+					Properties: smd.PropertyList{
+						{
+							Name: "folders",
+							Type: smd.Integer,
+						},
+						{
+							Name: "files",
+							Type: smd.Integer,
+						},
+					},
+				},
+				Errors: map[int]string{
+					404: "Deleted folder not found",
+					409: "Parent folder is deleted",
+				},
+			},
+			"MoveFolder": {
+				Description: `MoveFolder move Folder to destination folder. Moving folder with more than VFS.ConfirmThreshold files requires confirm.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "folderId",
+						Type: smd.Integer,
+					},
+					{
+						Name: "destinationFolderId",
+						Type: smd.Integer,
+					},
+					{
+						Name:        "confirm",
+						Optional:    true,
+						Description: `confirm move of more than VFS.ConfirmThreshold files`,
+						Type:        smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+				Errors: map[int]string{
+					409: "Destination folder is in folder subtree",
+					428: "Confirmation is required",
+				},
+			},
+			"CopyFolder": {
+				Description: `CopyFolder copies Folder with files into destination folder, sub folders are copied in recursive mode.
+Copied files share physical files via hard links, see CopyFiles. Folder quotas are not copied.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "folderId",
+						Type: smd.Integer,
+					},
+					{
+						Name: "destinationFolderId",
+						Type: smd.Integer,
+					},
+					{
+						Name:        "recursive",
+						Optional:    true,
+						Description: `copy sub folders with files`,
+						Type:        smd.Boolean,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "CopyFolderResponse",
+					Properties: smd.PropertyList{
+						{
+							Name:        "folderId",
+							Description: `new folder id`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "folders",
+							Description: `new folder ids including folder`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"type": smd.Integer,
+							},
+						},
+						{
+							Name: "files",
+							Type: smd.Array,
+							Items: map[string]string{
+								"type": smd.Integer,
+							},
+						},
+					},
+				},
+				Errors: map[int]string{
+					400: "Root folder",
+					404: "Folder not found",
+					409: "Destination folder is in folder subtree or file does not exist on disk",
+					413: "Folder quota exceeded",
+				},
+			},
+			"RenameFolder": {
+				Description: `RenameFolder change Folder name.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "folderId",
+						Type: smd.Integer,
+					},
+					{
+						Name: "name",
+						Type: smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+			},
+			"SetFolderQuota": {
+				Description: `SetFolderQuota sets max total files size and files count in folder subtree, nil removes quota.
+Uploads exceeding quota of folder or any parent folder are rejected with 413 error.
+Quotas are not checked on moving and restoring files and folders, so usage could exceed quota after these operations.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name: "folderId",
+						Type: smd.Integer,
+					},
+					{
+						Name:        "quotaBytes",
+						Optional:    true,
+						Description: `max total files size in bytes`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "quotaFiles",
+						Optional:    true,
+						Description: `max total files`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+				Errors: map[int]string{
+					400: "Invalid quota",
+					404: "Folder not found",
+				},
+			},
+			"GetUsage": {
+				Description: `GetUsage returns total files and files size of folder subtree and each direct sub folder subtree with quotas
+and total hashes and hashes size per namespace.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:     "rootFolderId",
+						Optional: true,
+						Type:     smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "UsageResponse",
+					Properties: smd.PropertyList{
+						{
+							Name:        "folders",
+							Description: `folder and its direct sub folders`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/FolderUsage",
+							},
+						},
+						{
+							Name: "namespaces",
+							Type: smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/NamespaceUsage",
+							},
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"FolderUsage": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.Integer,
+								},
+								{
+									Name: "name",
+									Type: smd.String,
+								},
+								{
+									Name: "files",
+									Type: smd.Integer,
+								},
+								{
+									Name: "filesSize",
+									Type: smd.Integer,
+								},
+								{
+									Name:     "quotaFiles",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+								{
+									Name:     "quotaBytes",
+									Optional: true,
+									Type:     smd.Integer,
+								},
+							},
+						},
+						"NamespaceUsage": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespace",
+									Type: smd.String,
+								},
+								{
+									Name: "hashes",
+									Type: smd.Integer,
+								},
+								{
+									Name: "filesSize",
+									Type: smd.Integer,
+								},
+							},
+						},
+					},
+				},
+				Errors: map[int]string{
+					404: "Folder not found",
+				},
+			},
+			"HelpUpload": {
+				Description: `HelpUpload returns a uploader help info.`,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Type:     smd.Object,
+					TypeName: "HelpUploadResponse",
+					Properties: smd.PropertyList{
+						{
+							Name: "temp",
+							Ref:  "#/definitions/HelpUploadItem",
+							Type: smd.Object,
+						},
+						{
+							Name: "queue",
+							Ref:  "#/definitions/HelpUploadItem",
+							Type: smd.Object,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"HelpUploadItem": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "url",
+									Type: smd.String,
+								},
+								{
+									Name: "params",
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"UrlByHash": {
+				Description: `UrlByHash get Url by hash, namespace and media type`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "hash",
+						Description: `media hash`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "mediaType",
+						Description: `type of media (possible values: small, medium, big, empty string)`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.String,
+				},
+			},
+			"UrlByHashList": {
+				Description: `UrlByHashList get Urls by hash list, with namespace and media type`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "hashList",
+						Description: `media hash list`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "mediaType",
+						Description: `type of media (possible values: small, medium, big, empty string)`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type:     smd.Array,
+					TypeName: "[]UrlByHashListResponse",
+					Items: map[string]string{
+						"$ref": "#/definitions/UrlByHashListResponse",
+					},
+					Definitions: map[string]smd.Definition{
+						"UrlByHashListResponse": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "hash",
+									Type: smd.String,
+								},
+								{
+									Name: "webPath",
+									Type: smd.String,
+								},
+							},
+						},
+					},
+				},
+			},
+			"DeleteHash": {
+				Description: `DeleteHash delete file by namespace and hash.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "hash",
+						Description: `media hash`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+				Errors: map[int]string{
+					404: "File not found by hash",
+				},
+			},
+			"GetHashColors": {
+				Description: `GetHashColors returns dominant color and color palette of indexed image with its size and blurhash.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "hash",
+						Description: `media hash`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "HashColors",
+					Properties: smd.PropertyList{
+						{
+							Name: "hash",
+							Type: smd.String,
+						},
+						{
+							Name: "width",
+							Type: smd.Integer,
+						},
+						{
+							Name: "height",
+							Type: smd.Integer,
+						},
+						{
+							Name:     "blurhash",
+							Optional: true,
+							Type:     smd.String,
+						},
+						{
+							Name:        "color",
+							Optional:    true,
+							Description: `dominant color, e.g. "#ff0000"`,
+							Type:        smd.String,
+						},
+						{
+							Name:        "palette",
+							Description: `most used colors, dominant is first`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"type": smd.String,
+							},
+						},
+					},
+				},
+				Errors: map[int]string{
+					404: "Hash not found",
+				},
+			},
+			"FindSimilar": {
+				Description: `FindSimilar returns indexed images from namespace similar to hash by perceptual hash.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "hash",
+						Description: `media hash`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "distance",
+						Optional:    true,
+						Description: `max hamming distance between perceptual hashes, from 0 to 64`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type:     smd.Array,
+					TypeName: "[]SimilarHash",
+					Items: map[string]string{
+						"$ref": "#/definitions/SimilarHash",
+					},
+					Definitions: map[string]smd.Definition{
+						"SimilarHash": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "hash",
+									Type: smd.String,
+								},
+								{
+									Name: "ext",
+									Type: smd.String,
+								},
+								{
+									Name: "webPath",
+									Type: smd.String,
+								},
+								{
+									Name: "width",
+									Type: smd.Integer,
+								},
+								{
+									Name: "height",
+									Type: smd.Integer,
+								},
+								{
+									Name:        "distance",
+									Description: `hamming distance between perceptual hashes`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+					},
+				},
+				Errors: map[int]string{
+					400: "Invalid distance",
+					404: "Hash not found or not indexed",
+				},
+			},
+			"GetHashInfo": {
+				Description: `GetHashInfo returns hash file info: extension, size, dimensions, blurhash and indexing status.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "hash",
+						Description: `media hash`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "HashInfoResponse",
+					Properties: smd.PropertyList{
+						{
+							Name: "hash",
+							Type: smd.String,
+						},
+						{
+							Name: "ext",
+							Type: smd.String,
+						},
+						{
+							Name: "size",
+							Type: smd.Integer,
+						},
+						{
+							Name: "width",
+							Type: smd.Integer,
+						},
+						{
+							Name: "height",
+							Type: smd.Integer,
+						},
+						{
+							Name:     "blurhash",
+							Optional: true,
+							Type:     smd.String,
+						},
+						{
+							Name:     "indexedAt",
+							Optional: true,
+							Type:     smd.String,
+						},
+						{
+							Name:        "error",
+							Description: `indexing error`,
+							Type:        smd.String,
+						},
+						{
+							Name:        "attempts",
+							Description: `failed indexing attempts`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "nextAttemptAt",
+							Optional:    true,
+							Description: `next indexing attempt for retried hash`,
+							Type:        smd.String,
+						},
+					},
+				},
+				Errors: map[int]string{
+					404: "Hash not found",
+				},
+			},
+			"GetHashInfoList": {
+				Description: `GetHashInfoList returns hash files info by hash list. Not found hashes are skipped.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "hashes",
+						Description: `media hash list, up to 1000 items`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type:     smd.Array,
+					TypeName: "[]HashInfoResponse",
+					Items: map[string]string{
+						"$ref": "#/definitions/HashInfoResponse",
+					},
+					Definitions: map[string]smd.Definition{
+						"HashInfoResponse": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "hash",
+									Type: smd.String,
+								},
+								{
+									Name: "ext",
+									Type: smd.String,
+								},
+								{
+									Name: "size",
+									Type: smd.Integer,
+								},
+								{
+									Name: "width",
+									Type: smd.Integer,
+								},
+								{
+									Name: "height",
+									Type: smd.Integer,
+								},
+								{
+									Name:     "blurhash",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name:     "indexedAt",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name:        "error",
+									Description: `indexing error`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "attempts",
+									Description: `failed indexing attempts`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "nextAttemptAt",
+									Optional:    true,
+									Description: `next indexing attempt for retried hash`,
+									Type:        smd.String,
+								},
+							},
+						},
+					},
+				},
+				Errors: map[int]string{
+					400: "Empty or too long hash list",
+				},
+			},
+			"GetFailedHashes": {
+				Description: `GetFailedHashes returns hashes from namespace that failed indexing after all attempts, recently failed first.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "page",
+						Optional:    true,
+						Description: `current page`,
+						Type:        smd.Integer,
+					},
+					{
+						Name:        "pageSize",
+						Optional:    true,
+						Description: `current pageSize`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type:     smd.Array,
+					TypeName: "[]HashInfoResponse",
+					Items: map[string]string{
+						"$ref": "#/definitions/HashInfoResponse",
+					},
+					Definitions: map[string]smd.Definition{
+						"HashInfoResponse": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "hash",
+									Type: smd.String,
+								},
+								{
+									Name: "ext",
+									Type: smd.String,
+								},
+								{
+									Name: "size",
+									Type: smd.Integer,
+								},
+								{
+									Name: "width",
+									Type: smd.Integer,
+								},
+								{
+									Name: "height",
+									Type: smd.Integer,
+								},
+								{
+									Name:     "blurhash",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name:     "indexedAt",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name:        "error",
+									Description: `indexing error`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "attempts",
+									Description: `failed indexing attempts`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "nextAttemptAt",
+									Optional:    true,
+									Description: `next indexing attempt for retried hash`,
+									Type:        smd.String,
+								},
+							},
+						},
+					},
+				},
+			},
+			"RequeueFailedHashes": {
+				Description: `RequeueFailedHashes resets failed hashes for indexing and returns total requeued hashes.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespace",
+						Description: `media namespace`,
+						Type:        smd.String,
+					},
+					{
+						Name:        "hashes",
+						Description: `media hash list, all failed hashes from namespace if empty`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Too long hash list",
+				},
+			},
+			"Reindex": {
+				Description: `Reindex resets indexed hashes matched by filter into indexing queue and returns total queued hashes.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "filter",
+						Description: `reindex filter, all hashes are reindexed for empty filter`,
+						Type:        smd.Object,
+						TypeName:    "ReindexFilter",
+						Properties: smd.PropertyList{
+							{
+								Name:        "namespace",
+								Optional:    true,
+								Description: `media namespace`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "ext",
+								Optional:    true,
+								Description: `media extension`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "createdFrom",
+								Optional:    true,
+								Description: `created at or after`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "createdTo",
+								Optional:    true,
+								Description: `created before`,
+								Type:        smd.String,
+							},
+							{
+								Name:        "erroredOnly",
+								Description: `hashes with indexing error only`,
+								Type:        smd.Boolean,
+							},
+							{
+								Name:        "missingBlurhash",
+								Description: `hashes without blurhash only`,
+								Type:        smd.Boolean,
+							},
+						},
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Integer,
+				},
+				Errors: map[int]string{
+					400: "Invalid created at range",
+				},
+			},
+			"IndexerStatus": {
+				Description: `IndexerStatus returns indexer progress: queue per namespace, throughput, ETA and scan status.`,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "IndexerStatus",
+					Properties: smd.PropertyList{
+						{
+							Name:        "namespaces",
+							Description: `ordered by IndexNamespacesPriority, then by name`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/NamespaceQueue",
+							},
+						},
+						{
+							Name: "pending",
+							Type: smd.Integer,
+						},
+						{
+							Name: "failed",
+							Type: smd.Integer,
+						},
+						{
+							Name:        "throughput",
+							Description: `indexed hashes per minute for last 5 minutes`,
+							Type:        smd.Float,
+						},
+						{
+							Name:        "eta",
+							Optional:    true,
+							Description: `estimated seconds to process queue, empty if not calculated`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "scanning",
+							Description: `ScanFiles is running`,
+							Type:        smd.Boolean,
+						},
+						{
+							Name:     "lastScan",
+							Optional: true,
+							Ref:      "#/definitions/ScanResults",
+							Type:     smd.Object,
+						},
+						{
+							Name:     "lastScanAt",
+							Optional: true,
+							Type:     smd.String,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"NamespaceQueue": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespace",
+									Type: smd.String,
+								},
+								{
+									Name:        "pending",
+									Description: `hashes in queue, including retried`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "retrying",
+									Description: `hashes waiting for next attempt after transient error`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "failed",
+									Description: `hashes failed after all attempts`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"ScanResults": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "scanned",
+									Type: smd.Integer,
+								},
+								{
+									Name: "added",
+									Type: smd.Integer,
+								},
+								{
+									Name: "duration",
+									Ref:  "#/definitions/time.Duration",
+									Type: smd.Object,
+								},
+								{
+									Name: "missing",
+									Description: `files scan results
+files marked as not existing`,
+									Type: smd.Integer,
+								},
+								{
+									Name:        "restored",
+									Description: `files marked as existing again`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "imported",
+									Description: `unknown files added into unsorted folder`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"time.Duration": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					503: "Indexer is disabled",
+				},
+			},
+			"NamespaceStats": {
+				Description: `NamespaceStats returns hashes count, size, average dimensions and indexed vs pending hashes per namespace and extension
+with daily growth for last 30 days. Stats are refreshed in background, see UpdatedAt.`,
+				Parameters: []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "NamespaceStatsResponse",
+					Properties: smd.PropertyList{
+						{
+							Name:        "namespaces",
+							Description: `sorted by namespace`,
+							Type:        smd.Array,
+							Items: map[string]string{
+								"$ref": "#/definitions/NamespaceStats",
+							},
+						},
+						{
+							Name: "updatedAt",
+							Type: smd.String,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"NamespaceStats": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespace",
+									Type: smd.String,
+								},
+								{
+									Name: "hashes",
+									Type: smd.Integer,
+								},
+								{
+									Name: "filesSize",
+									Type: smd.Integer,
+								},
+								{
+									Name: "indexed",
+									Type: smd.Integer,
+								},
+								{
+									Name: "pending",
+									Type: smd.Integer,
+								},
+								{
+									Name:        "dailyHashes",
+									Description: `average created hashes per day for last 30 days`,
+									Type:        smd.Float,
+								},
+								{
+									Name:        "dailyFilesSize",
+									Description: `average created hashes size per day for last 30 days`,
+									Type:        smd.Float,
+								},
+								{
+									Name: "extensions",
+									Type: smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/ExtensionStats",
+									},
+								},
+								{
+									Name:        "growth",
+									Description: `days with created hashes for last 30 days`,
+									Type:        smd.Array,
+									Items: map[string]string{
+										"$ref": "#/definitions/DailyGrowth",
+									},
+								},
+							},
+						},
+						"ExtensionStats": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "extension",
+									Type: smd.String,
+								},
+								{
+									Name: "hashes",
+									Type: smd.Integer,
+								},
+								{
+									Name: "filesSize",
+									Type: smd.Integer,
+								},
+								{
+									Name:        "avgWidth",
+									Description: `average width of hashes with dimensions`,
+									Type:        smd.Float,
+								},
+								{
+									Name:        "avgHeight",
+									Description: `average height of hashes with dimensions`,
+									Type:        smd.Float,
+								},
+								{
+									Name: "indexed",
+									Type: smd.Integer,
+								},
+								{
+									Name: "pending",
+									Type: smd.Integer,
+								},
+							},
+						},
+						"DailyGrowth": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name:        "date",
+									Description: `YYYY-MM-DD`,
+									Type:        smd.String,
+								},
+								{
+									Name: "hashes",
+									Type: smd.Integer,
+								},
+								{
+									Name: "filesSize",
+									Type: smd.Integer,
+								},
+							},
+						},
+					},
+				},
+				Errors: map[int]string{
+					503: "Stats are not ready",
+				},
+			},
+			"StartScan": {
+				Description: `StartScan starts files scan in background and returns scan job. Scan reads media folder and loads new files into indexing queue.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "namespaces",
+						Description: `namespaces for scan, all namespaces if empty`,
+						Type:        smd.Array,
+						TypeName:    "[]",
+						Items: map[string]string{
+							"type": smd.String,
+						},
+					},
+					{
+						Name:        "incremental",
+						Optional:    true,
+						Description: `skip hash directories not modified since last completed scan of namespace`,
+						Type:        smd.Boolean,
+					},
+					{
+						Name:        "files",
+						Optional:    true,
+						Description: `scan YYYYMM directories and update vfsFiles.fileExists instead of hashes`,
+						Type:        smd.Boolean,
+					},
+					{
+						Name:        "unsortedFolderId",
+						Optional:    true,
+						Description: `folder for unknown files found by files scan, files are not imported if empty`,
+						Type:        smd.Integer,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "ScanJob",
+					Properties: smd.PropertyList{
+						{
+							Name: "id",
+							Type: smd.String,
+						},
+						{
+							Name:        "status",
+							Description: `running, done, failed or canceled`,
+							Type:        smd.String,
+						},
+						{
+							Name: "options",
+							Ref:  "#/definitions/ScanOptions",
+							Type: smd.Object,
+						},
+						{
+							Name: "startedAt",
+							Type: smd.String,
+						},
+						{
+							Name:     "finishedAt",
+							Optional: true,
+							Type:     smd.String,
+						},
+						{
+							Name:        "walked",
+							Description: `total walked files`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "skippedDirs",
+							Description: `not modified hash directories skipped by incremental scan`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "currentDir",
+							Description: `current directory relative to vfs path`,
+							Type:        smd.String,
+						},
+						{
+							Name:        "results",
+							Optional:    true,
+							Description: `results for finished job`,
+							Ref:         "#/definitions/ScanResults",
+							Type:        smd.Object,
+						},
+						{
+							Name: "error",
+							Type: smd.String,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"ScanOptions": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespaces",
+									Description: `Namespaces is a list of namespaces for scan, all namespaces are scanned if empty.
+DefaultNamespace or empty string is used for public namespace.`,
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+								{
+									Name:        "incremental",
+									Description: `Incremental skips hash directories not modified since last completed scan of namespace.`,
+									Type:        smd.Boolean,
+								},
+								{
+									Name: "files",
+									Description: `Files scans YYYYMM directories of public namespace instead of hashes and updates vfsFiles.fileExists.
+Namespaces and Incremental are ignored for files scan.`,
+									Type: smd.Boolean,
+								},
+								{
+									Name:        "unsortedFolderId",
+									Optional:    true,
+									Description: `UnsortedFolderID is a folder for files not found in vfsFiles, unknown files are not imported if empty.`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"ScanResults": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "scanned",
+									Type: smd.Integer,
+								},
+								{
+									Name: "added",
+									Type: smd.Integer,
+								},
+								{
+									Name: "duration",
+									Ref:  "#/definitions/time.Duration",
+									Type: smd.Object,
+								},
+								{
+									Name: "missing",
+									Description: `files scan results
+files marked as not existing`,
+									Type: smd.Integer,
+								},
+								{
+									Name:        "restored",
+									Description: `files marked as existing again`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "imported",
+									Description: `unknown files added into unsorted folder`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"time.Duration": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					400: "Invalid namespace or folder",
+					409: "Scan is already running",
+					503: "Indexer is disabled",
+				},
+			},
+			"GetScanJob": {
+				Description: `GetScanJob returns scan job with progress or results.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Description: `scan job id`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Optional: true,
+					Type:     smd.Object,
+					TypeName: "ScanJob",
+					Properties: smd.PropertyList{
+						{
+							Name: "id",
+							Type: smd.String,
+						},
+						{
+							Name:        "status",
+							Description: `running, done, failed or canceled`,
+							Type:        smd.String,
+						},
+						{
+							Name: "options",
+							Ref:  "#/definitions/ScanOptions",
+							Type: smd.Object,
+						},
+						{
+							Name: "startedAt",
+							Type: smd.String,
+						},
+						{
+							Name:     "finishedAt",
+							Optional: true,
+							Type:     smd.String,
+						},
+						{
+							Name:        "walked",
+							Description: `total walked files`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "skippedDirs",
+							Description: `not modified hash directories skipped by incremental scan`,
+							Type:        smd.Integer,
+						},
+						{
+							Name:        "currentDir",
+							Description: `current directory relative to vfs path`,
+							Type:        smd.String,
+						},
+						{
+							Name:        "results",
+							Optional:    true,
+							Description: `results for finished job`,
+							Ref:         "#/definitions/ScanResults",
+							Type:        smd.Object,
+						},
+						{
+							Name: "error",
+							Type: smd.String,
+						},
+					},
+					Definitions: map[string]smd.Definition{
+						"ScanOptions": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespaces",
+									Description: `Namespaces is a list of namespaces for scan, all namespaces are scanned if empty.
+DefaultNamespace or empty string is used for public namespace.`,
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+								{
+									Name:        "incremental",
+									Description: `Incremental skips hash directories not modified since last completed scan of namespace.`,
+									Type:        smd.Boolean,
+								},
+								{
+									Name: "files",
+									Description: `Files scans YYYYMM directories of public namespace instead of hashes and updates vfsFiles.fileExists.
+Namespaces and Incremental are ignored for files scan.`,
+									Type: smd.Boolean,
+								},
+								{
+									Name:        "unsortedFolderId",
+									Optional:    true,
+									Description: `UnsortedFolderID is a folder for files not found in vfsFiles, unknown files are not imported if empty.`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"ScanResults": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "scanned",
+									Type: smd.Integer,
+								},
+								{
+									Name: "added",
+									Type: smd.Integer,
+								},
+								{
+									Name: "duration",
+									Ref:  "#/definitions/time.Duration",
+									Type: smd.Object,
+								},
+								{
+									Name: "missing",
+									Description: `files scan results
+files marked as not existing`,
+									Type: smd.Integer,
+								},
+								{
+									Name:        "restored",
+									Description: `files marked as existing again`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "imported",
+									Description: `unknown files added into unsorted folder`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"time.Duration": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					404: "Scan job not found",
+					503: "Indexer is disabled",
+				},
+			},
+			"GetScanJobs": {
+				Description: `GetScanJobs returns last scan jobs, newest first.`,
+				Parameters:  []smd.JSONSchema{},
+				Returns: smd.JSONSchema{
+					Type:     smd.Array,
+					TypeName: "[]ScanJob",
+					Items: map[string]string{
+						"$ref": "#/definitions/ScanJob",
+					},
+					Definitions: map[string]smd.Definition{
+						"ScanJob": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "id",
+									Type: smd.String,
+								},
+								{
+									Name:        "status",
+									Description: `running, done, failed or canceled`,
+									Type:        smd.String,
+								},
+								{
+									Name: "options",
+									Ref:  "#/definitions/ScanOptions",
+									Type: smd.Object,
+								},
+								{
+									Name: "startedAt",
+									Type: smd.String,
+								},
+								{
+									Name:     "finishedAt",
+									Optional: true,
+									Type:     smd.String,
+								},
+								{
+									Name:        "walked",
+									Description: `total walked files`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "skippedDirs",
+									Description: `not modified hash directories skipped by incremental scan`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "currentDir",
+									Description: `current directory relative to vfs path`,
+									Type:        smd.String,
+								},
+								{
+									Name:        "results",
+									Optional:    true,
+									Description: `results for finished job`,
+									Ref:         "#/definitions/ScanResults",
+									Type:        smd.Object,
+								},
+								{
+									Name: "error",
+									Type: smd.String,
+								},
+							},
+						},
+						"ScanOptions": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "namespaces",
+									Description: `Namespaces is a list of namespaces for scan, all namespaces are scanned if empty.
+DefaultNamespace or empty string is used for public namespace.`,
+									Type: smd.Array,
+									Items: map[string]string{
+										"type": smd.String,
+									},
+								},
+								{
+									Name:        "incremental",
+									Description: `Incremental skips hash directories not modified since last completed scan of namespace.`,
+									Type:        smd.Boolean,
+								},
+								{
+									Name: "files",
+									Description: `Files scans YYYYMM directories of public namespace instead of hashes and updates vfsFiles.fileExists.
+Namespaces and Incremental are ignored for files scan.`,
+									Type: smd.Boolean,
+								},
+								{
+									Name:        "unsortedFolderId",
+									Optional:    true,
+									Description: `UnsortedFolderID is a folder for files not found in vfsFiles, unknown files are not imported if empty.`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"ScanResults": {
+							Type: "object",
+							Properties: smd.PropertyList{
+								{
+									Name: "scanned",
+									Type: smd.Integer,
+								},
+								{
+									Name: "added",
+									Type: smd.Integer,
+								},
+								{
+									Name: "duration",
+									Ref:  "#/definitions/time.Duration",
+									Type: smd.Object,
+								},
+								{
+									Name: "missing",
+									Description: `files scan results
+files marked as not existing`,
+									Type: smd.Integer,
+								},
+								{
+									Name:        "restored",
+									Description: `files marked as existing again`,
+									Type:        smd.Integer,
+								},
+								{
+									Name:        "imported",
+									Description: `unknown files added into unsorted folder`,
+									Type:        smd.Integer,
+								},
+							},
+						},
+						"time.Duration": {
+							Type:       "object",
+							Properties: smd.PropertyList{},
+						},
+					},
+				},
+				Errors: map[int]string{
+					503: "Indexer is disabled",
+				},
+			},
+			"CancelScan": {
+				Description: `CancelScan cancels running scan job.`,
+				Parameters: []smd.JSONSchema{
+					{
+						Name:        "id",
+						Description: `scan job id`,
+						Type:        smd.String,
+					},
+				},
+				Returns: smd.JSONSchema{
+					Type: smd.Boolean,
+				},
+				Errors: map[int]string{
+					404: "Running scan job not found",
+					503: "Indexer is disabled",
+				},
+			},
+		},
+	}
+}
+
+// Invoke is as generated code from zenrpc cmd
+func (s Service) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
+	resp := zenrpc.Response{}
+	var err error
+
+	switch method {
+	case RPC.Service.GetFolder:
+		var args = struct {
+			RootFolderId *int `json:"rootFolderId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"rootFolderId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:rootFolderId=1
+		if args.RootFolderId == nil {
+			var v int = 1
+			args.RootFolderId = &v
+		}
+
+		resp.Set(s.GetFolder(ctx, *args.RootFolderId))
+
+	case RPC.Service.GetFolderTree:
+		var args = struct {
+			RootFolderId *int  `json:"rootFolderId"`
+			Depth        *int  `json:"depth"`
+			WithDeleted  *bool `json:"withDeleted"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"rootFolderId", "depth", "withDeleted"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:depth=0 max sub folders level, 0 is unlimited
+		if args.Depth == nil {
+			var v int = 0
+			args.Depth = &v
+		}
+
+		//zenrpc:rootFolderId=1
+		if args.RootFolderId == nil {
+			var v int = 1
+			args.RootFolderId = &v
+		}
+
+		//zenrpc:withDeleted=false include deleted folders
+		if args.WithDeleted == nil {
+			var v bool = false
+			args.WithDeleted = &v
+		}
+
+		resp.Set(s.GetFolderTree(ctx, *args.RootFolderId, *args.Depth, *args.WithDeleted))
+
+	case RPC.Service.GetFolderBranch:
+		var args = struct {
+			FolderId int `json:"folderId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.GetFolderBranch(ctx, args.FolderId))
+
+	case RPC.Service.GetFiles:
+		var args = struct {
+			FolderId     int      `json:"folderId"`
+			Query        *string  `json:"query"`
+			SortField    *string  `json:"sortField"`
+			IsDescending *bool    `json:"isDescending"`
+			Page         *int     `json:"page"`
+			PageSize     *int     `json:"pageSize"`
+			Tags         []string `json:"tags"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "query", "sortField", "isDescending", "page", "pageSize", "tags"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:isDescending=true asc = false, desc = true
+		if args.IsDescending == nil {
+			var v bool = true
+			args.IsDescending = &v
+		}
+
+		//zenrpc:page=0 current page
+		if args.Page == nil {
+			var v int = 0
+			args.Page = &v
+		}
+
+		//zenrpc:pageSize=100 current pageSize
+		if args.PageSize == nil {
+			var v int = 100
+			args.PageSize = &v
+		}
+
+		//zenrpc:sortField="createdAt" createdAt, title or fileSize
+		if args.SortField == nil {
+			var v string = "createdAt"
+			args.SortField = &v
+		}
+
+		resp.Set(s.GetFiles(ctx, args.FolderId, args.Query, *args.SortField, *args.IsDescending, *args.Page, *args.PageSize, args.Tags))
+
+	case RPC.Service.SearchFiles:
+		var args = struct {
+			Search       FileSearch `json:"search"`
+			SortField    *string    `json:"sortField"`
+			IsDescending *bool      `json:"isDescending"`
+			Page         *int       `json:"page"`
+			PageSize     *int       `json:"pageSize"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"search", "sortField", "isDescending", "page", "pageSize"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:isDescending=true asc = false, desc = true
+		if args.IsDescending == nil {
+			var v bool = true
+			args.IsDescending = &v
+		}
+
+		//zenrpc:page=0 current page
+		if args.Page == nil {
+			var v int = 0
+			args.Page = &v
+		}
+
+		//zenrpc:pageSize=100 current pageSize
+		if args.PageSize == nil {
+			var v int = 100
+			args.PageSize = &v
+		}
+
+		//zenrpc:sortField="createdAt" createdAt, title or fileSize
+		if args.SortField == nil {
+			var v string = "createdAt"
+			args.SortField = &v
+		}
+
+		resp.Set(s.SearchFiles(ctx, args.Search, *args.SortField, *args.IsDescending, *args.Page, *args.PageSize))
+
+	case RPC.Service.CountFiles:
+		var args = struct {
+			FolderId int      `json:"folderId"`
+			Query    *string  `json:"query"`
+			Tags     []string `json:"tags"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "query", "tags"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.CountFiles(ctx, args.FolderId, args.Query, args.Tags))
+
+	case RPC.Service.MoveFiles:
+		var args = struct {
+			FileIds             []int64 `json:"fileIds"`
+			DestinationFolderId int     `json:"destinationFolderId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "destinationFolderId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.MoveFiles(ctx, args.FileIds, args.DestinationFolderId))
+
+	case RPC.Service.CopyFiles:
+		var args = struct {
+			FileIds             []int64 `json:"fileIds"`
+			DestinationFolderId int     `json:"destinationFolderId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "destinationFolderId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.CopyFiles(ctx, args.FileIds, args.DestinationFolderId))
+
+	case RPC.Service.DeleteFiles:
+		var args = struct {
+			FileIds []int64 `json:"fileIds"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.DeleteFiles(ctx, args.FileIds))
+
+	case RPC.Service.GetTags:
+		resp.Set(s.GetTags(ctx))
+
+	case RPC.Service.AddFilesTags:
+		var args = struct {
+			FileIds []int64  `json:"fileIds"`
+			Tags    []string `json:"tags"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "tags"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.AddFilesTags(ctx, args.FileIds, args.Tags))
+
+	case RPC.Service.RemoveFilesTags:
+		var args = struct {
+			FileIds []int64  `json:"fileIds"`
+			Tags    []string `json:"tags"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "tags"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.RemoveFilesTags(ctx, args.FileIds, args.Tags))
+
+	case RPC.Service.SetFilesMeta:
+		var args = struct {
+			FileIds []int64           `json:"fileIds"`
+			Meta    map[string]string `json:"meta"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "meta"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.SetFilesMeta(ctx, args.FileIds, args.Meta))
+
+	case RPC.Service.RemoveFilesMeta:
+		var args = struct {
+			FileIds []int64  `json:"fileIds"`
+			Keys    []string `json:"keys"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "keys"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.RemoveFilesMeta(ctx, args.FileIds, args.Keys))
+
+	case RPC.Service.SetFileTexts:
+		var args = struct {
+			FileId int      `json:"fileId"`
+			Lang   string   `json:"lang"`
+			Text   FileText `json:"text"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileId", "lang", "text"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.SetFileTexts(ctx, args.FileId, args.Lang, args.Text))
+
+	case RPC.Service.PublishFiles:
+		var args = struct {
+			FileIds     []int64 `json:"fileIds"`
+			IsPublished bool    `json:"isPublished"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "isPublished"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.PublishFiles(ctx, args.FileIds, args.IsPublished))
+
+	case RPC.Service.GetFileVersions:
+		var args = struct {
+			FileId int `json:"fileId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.GetFileVersions(ctx, args.FileId))
+
+	case RPC.Service.RollbackFile:
+		var args = struct {
+			FileId    int `json:"fileId"`
+			VersionId int `json:"versionId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileId", "versionId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.RollbackFile(ctx, args.FileId, args.VersionId))
+
+	case RPC.Service.SetFilePhysicalName:
+		var args = struct {
+			FileId int    `json:"fileId"`
+			Name   string `json:"name"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileId", "name"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.SetFilePhysicalName(ctx, args.FileId, args.Name))
+
+	case RPC.Service.SearchFolderByFileId:
+		var args = struct {
+			FileId int `json:"fileId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.SearchFolderByFileId(ctx, args.FileId))
+
+	case RPC.Service.SearchFolderByFile:
+		var args = struct {
+			Filename string `json:"filename"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"filename"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.SearchFolderByFile(ctx, args.Filename))
+
+	case RPC.Service.GetFavorites:
+		resp.Set(s.GetFavorites(ctx))
+
+	case RPC.Service.ManageFavorites:
+		var args = struct {
+			FolderId      int  `json:"folderId"`
+			IsInFavorites bool `json:"isInFavorites"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "isInFavorites"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
 
-// Invoke is as generated code from zenrpc cmd
-func (s Service) Invoke(ctx context.Context, method string, params json.RawMessage) zenrpc.Response {
-	resp := zenrpc.Response{}
-	var err error
+		resp.Set(s.ManageFavorites(ctx, args.FolderId, args.IsInFavorites))
 
-	switch method {
-	case RPC.Service.GetFolder:
+	case RPC.Service.CreateFolder:
 		var args = struct {
-			RootFolderId *int `json:"rootFolderId"`
+			RootFolderId int    `json:"rootFolderId"`
+			Name         string `json:"name"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"rootFolderId"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"rootFolderId", "name"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -747,21 +3838,17 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		//zenrpc:rootFolderId=1
-		if args.RootFolderId == nil {
-			var v int = 1
-			args.RootFolderId = &v
-		}
-
-		resp.Set(s.GetFolder(ctx, *args.RootFolderId))
+		resp.Set(s.CreateFolder(ctx, args.RootFolderId, args.Name))
 
-	case RPC.Service.GetFolderBranch:
+	case RPC.Service.DeleteFolder:
 		var args = struct {
-			FolderId int `json:"folderId"`
+			FolderId  int   `json:"folderId"`
+			Recursive *bool `json:"recursive"`
+			Confirm   *bool `json:"confirm"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"folderId"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "recursive", "confirm"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -772,20 +3859,16 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.GetFolderBranch(ctx, args.FolderId))
+		resp.Set(s.DeleteFolder(ctx, args.FolderId, args.Recursive, args.Confirm))
 
-	case RPC.Service.GetFiles:
+	case RPC.Service.GetTrash:
 		var args = struct {
-			FolderId     int     `json:"folderId"`
-			Query        *string `json:"query"`
-			SortField    *string `json:"sortField"`
-			IsDescending *bool   `json:"isDescending"`
-			Page         *int    `json:"page"`
-			PageSize     *int    `json:"pageSize"`
+			Page     *int `json:"page"`
+			PageSize *int `json:"pageSize"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"folderId", "query", "sortField", "isDescending", "page", "pageSize"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"page", "pageSize"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -796,40 +3879,46 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		//zenrpc:isDescending=true asc = false, desc = true
-		if args.IsDescending == nil {
-			var v bool = true
-			args.IsDescending = &v
-		}
-
-		//zenrpc:page=0 current page
+		//zenrpc:page=0 current page for files
 		if args.Page == nil {
 			var v int = 0
 			args.Page = &v
 		}
 
-		//zenrpc:pageSize=100 current pageSize
+		//zenrpc:pageSize=100 current pageSize for files
 		if args.PageSize == nil {
 			var v int = 100
 			args.PageSize = &v
 		}
 
-		//zenrpc:sortField="createdAt" createdAt, title or fileSize
-		if args.SortField == nil {
-			var v string = "createdAt"
-			args.SortField = &v
+		resp.Set(s.GetTrash(ctx, *args.Page, *args.PageSize))
+
+	case RPC.Service.RestoreFiles:
+		var args = struct {
+			FileIds []int64 `json:"fileIds"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"fileIds"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
 		}
 
-		resp.Set(s.GetFiles(ctx, args.FolderId, args.Query, *args.SortField, *args.IsDescending, *args.Page, *args.PageSize))
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
 
-	case RPC.Service.CountFiles:
+		resp.Set(s.RestoreFiles(ctx, args.FileIds))
+
+	case RPC.Service.RestoreFolder:
 		var args = struct {
-			FolderId int     `json:"folderId"`
-			Query    *string `json:"query"`
+			FolderId int `json:"folderId"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"folderId", "query"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -840,16 +3929,17 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.CountFiles(ctx, args.FolderId, args.Query))
+		resp.Set(s.RestoreFolder(ctx, args.FolderId))
 
-	case RPC.Service.MoveFiles:
+	case RPC.Service.MoveFolder:
 		var args = struct {
-			FileIds             []int64 `json:"fileIds"`
-			DestinationFolderId int     `json:"destinationFolderId"`
+			FolderId            int   `json:"folderId"`
+			DestinationFolderId int   `json:"destinationFolderId"`
+			Confirm             *bool `json:"confirm"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"fileIds", "destinationFolderId"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "destinationFolderId", "confirm"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -860,15 +3950,23 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.MoveFiles(ctx, args.FileIds, args.DestinationFolderId))
+		//zenrpc:confirm=false confirm move of more than VFS.ConfirmThreshold files
+		if args.Confirm == nil {
+			var v bool = false
+			args.Confirm = &v
+		}
 
-	case RPC.Service.DeleteFiles:
+		resp.Set(s.MoveFolder(ctx, args.FolderId, args.DestinationFolderId, *args.Confirm))
+
+	case RPC.Service.CopyFolder:
 		var args = struct {
-			FileIds []int64 `json:"fileIds"`
+			FolderId            int   `json:"folderId"`
+			DestinationFolderId int   `json:"destinationFolderId"`
+			Recursive           *bool `json:"recursive"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"fileIds"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "destinationFolderId", "recursive"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -879,16 +3977,22 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.DeleteFiles(ctx, args.FileIds))
+		//zenrpc:recursive=false copy sub folders with files
+		if args.Recursive == nil {
+			var v bool = false
+			args.Recursive = &v
+		}
 
-	case RPC.Service.SetFilePhysicalName:
+		resp.Set(s.CopyFolder(ctx, args.FolderId, args.DestinationFolderId, *args.Recursive))
+
+	case RPC.Service.RenameFolder:
 		var args = struct {
-			FileId int    `json:"fileId"`
-			Name   string `json:"name"`
+			FolderId int    `json:"folderId"`
+			Name     string `json:"name"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"fileId", "name"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "name"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -899,15 +4003,17 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.SetFilePhysicalName(ctx, args.FileId, args.Name))
+		resp.Set(s.RenameFolder(ctx, args.FolderId, args.Name))
 
-	case RPC.Service.SearchFolderByFileId:
+	case RPC.Service.SetFolderQuota:
 		var args = struct {
-			FileId int `json:"fileId"`
+			FolderId   int    `json:"folderId"`
+			QuotaBytes *int64 `json:"quotaBytes"`
+			QuotaFiles *int   `json:"quotaFiles"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"fileId"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"folderId", "quotaBytes", "quotaFiles"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -918,15 +4024,15 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.SearchFolderByFileId(ctx, args.FileId))
+		resp.Set(s.SetFolderQuota(ctx, args.FolderId, args.QuotaBytes, args.QuotaFiles))
 
-	case RPC.Service.SearchFolderByFile:
+	case RPC.Service.GetUsage:
 		var args = struct {
-			Filename string `json:"filename"`
+			RootFolderId *int `json:"rootFolderId"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"filename"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"rootFolderId"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -937,19 +4043,26 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.SearchFolderByFile(ctx, args.Filename))
+		//zenrpc:rootFolderId=1
+		if args.RootFolderId == nil {
+			var v int = 1
+			args.RootFolderId = &v
+		}
 
-	case RPC.Service.GetFavorites:
-		resp.Set(s.GetFavorites(ctx))
+		resp.Set(s.GetUsage(ctx, *args.RootFolderId))
 
-	case RPC.Service.ManageFavorites:
+	case RPC.Service.HelpUpload:
+		resp.Set(s.HelpUpload())
+
+	case RPC.Service.UrlByHash:
 		var args = struct {
-			FolderId      int  `json:"folderId"`
-			IsInFavorites bool `json:"isInFavorites"`
+			Hash      string `json:"hash"`
+			Namespace string `json:"namespace"`
+			MediaType string `json:"mediaType"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"folderId", "isInFavorites"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"hash", "namespace", "mediaType"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -960,16 +4073,17 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.ManageFavorites(ctx, args.FolderId, args.IsInFavorites))
+		resp.Set(s.UrlByHash(ctx, args.Hash, args.Namespace, args.MediaType))
 
-	case RPC.Service.CreateFolder:
+	case RPC.Service.UrlByHashList:
 		var args = struct {
-			RootFolderId int    `json:"rootFolderId"`
-			Name         string `json:"name"`
+			HashList  []string `json:"hashList"`
+			Namespace string   `json:"namespace"`
+			MediaType string   `json:"mediaType"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"rootFolderId", "name"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"hashList", "namespace", "mediaType"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -980,15 +4094,16 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.CreateFolder(ctx, args.RootFolderId, args.Name))
+		resp.Set(s.UrlByHashList(ctx, args.HashList, args.Namespace, args.MediaType))
 
-	case RPC.Service.DeleteFolder:
+	case RPC.Service.DeleteHash:
 		var args = struct {
-			FolderId int `json:"folderId"`
+			Namespace string `json:"namespace"`
+			Hash      string `json:"hash"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"folderId"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"namespace", "hash"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -999,16 +4114,16 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.DeleteFolder(ctx, args.FolderId))
+		resp.Set(s.DeleteHash(ctx, args.Namespace, args.Hash))
 
-	case RPC.Service.MoveFolder:
+	case RPC.Service.GetHashColors:
 		var args = struct {
-			FolderId            int `json:"folderId"`
-			DestinationFolderId int `json:"destinationFolderId"`
+			Namespace string `json:"namespace"`
+			Hash      string `json:"hash"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"folderId", "destinationFolderId"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"namespace", "hash"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -1019,16 +4134,17 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.MoveFolder(ctx, args.FolderId, args.DestinationFolderId))
+		resp.Set(s.GetHashColors(ctx, args.Namespace, args.Hash))
 
-	case RPC.Service.RenameFolder:
+	case RPC.Service.FindSimilar:
 		var args = struct {
-			FolderId int    `json:"folderId"`
-			Name     string `json:"name"`
+			Namespace string `json:"namespace"`
+			Hash      string `json:"hash"`
+			Distance  *int   `json:"distance"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"folderId", "name"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"namespace", "hash", "distance"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -1039,20 +4155,22 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.RenameFolder(ctx, args.FolderId, args.Name))
+		//zenrpc:distance=10 max hamming distance between perceptual hashes, from 0 to 64
+		if args.Distance == nil {
+			var v int = 10
+			args.Distance = &v
+		}
 
-	case RPC.Service.HelpUpload:
-		resp.Set(s.HelpUpload())
+		resp.Set(s.FindSimilar(ctx, args.Namespace, args.Hash, *args.Distance))
 
-	case RPC.Service.UrlByHash:
+	case RPC.Service.GetHashInfo:
 		var args = struct {
-			Hash      string `json:"hash"`
 			Namespace string `json:"namespace"`
-			MediaType string `json:"mediaType"`
+			Hash      string `json:"hash"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"hash", "namespace", "mediaType"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"namespace", "hash"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -1063,17 +4181,16 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.UrlByHash(ctx, args.Hash, args.Namespace, args.MediaType))
+		resp.Set(s.GetHashInfo(ctx, args.Namespace, args.Hash))
 
-	case RPC.Service.UrlByHashList:
+	case RPC.Service.GetHashInfoList:
 		var args = struct {
-			HashList  []string `json:"hashList"`
 			Namespace string   `json:"namespace"`
-			MediaType string   `json:"mediaType"`
+			Hashes    []string `json:"hashes"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"hashList", "namespace", "mediaType"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"namespace", "hashes"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -1084,16 +4201,17 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.UrlByHashList(ctx, args.HashList, args.Namespace, args.MediaType))
+		resp.Set(s.GetHashInfoList(ctx, args.Namespace, args.Hashes))
 
-	case RPC.Service.DeleteHash:
+	case RPC.Service.GetFailedHashes:
 		var args = struct {
 			Namespace string `json:"namespace"`
-			Hash      string `json:"hash"`
+			Page      *int   `json:"page"`
+			PageSize  *int   `json:"pageSize"`
 		}{}
 
 		if zenrpc.IsArray(params) {
-			if params, err = zenrpc.ConvertToObject([]string{"namespace", "hash"}, params); err != nil {
+			if params, err = zenrpc.ConvertToObject([]string{"namespace", "page", "pageSize"}, params); err != nil {
 				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
 			}
 		}
@@ -1104,7 +4222,139 @@ func (s Service) Invoke(ctx context.Context, method string, params json.RawMessa
 			}
 		}
 
-		resp.Set(s.DeleteHash(ctx, args.Namespace, args.Hash))
+		//zenrpc:page=0 current page
+		if args.Page == nil {
+			var v int = 0
+			args.Page = &v
+		}
+
+		//zenrpc:pageSize=100 current pageSize
+		if args.PageSize == nil {
+			var v int = 100
+			args.PageSize = &v
+		}
+
+		resp.Set(s.GetFailedHashes(ctx, args.Namespace, *args.Page, *args.PageSize))
+
+	case RPC.Service.RequeueFailedHashes:
+		var args = struct {
+			Namespace string   `json:"namespace"`
+			Hashes    []string `json:"hashes"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"namespace", "hashes"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.RequeueFailedHashes(ctx, args.Namespace, args.Hashes))
+
+	case RPC.Service.Reindex:
+		var args = struct {
+			Filter ReindexFilter `json:"filter"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"filter"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.Reindex(ctx, args.Filter))
+
+	case RPC.Service.IndexerStatus:
+		resp.Set(s.IndexerStatus(ctx))
+
+	case RPC.Service.NamespaceStats:
+		resp.Set(s.NamespaceStats(ctx))
+
+	case RPC.Service.StartScan:
+		var args = struct {
+			Namespaces       []string `json:"namespaces"`
+			Incremental      *bool    `json:"incremental"`
+			Files            *bool    `json:"files"`
+			UnsortedFolderId *int     `json:"unsortedFolderId"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"namespaces", "incremental", "files", "unsortedFolderId"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		//zenrpc:files=false scan YYYYMM directories and update vfsFiles.fileExists instead of hashes
+		if args.Files == nil {
+			var v bool = false
+			args.Files = &v
+		}
+
+		//zenrpc:incremental=false skip hash directories not modified since last completed scan of namespace
+		if args.Incremental == nil {
+			var v bool = false
+			args.Incremental = &v
+		}
+
+		resp.Set(s.StartScan(ctx, args.Namespaces, *args.Incremental, *args.Files, args.UnsortedFolderId))
+
+	case RPC.Service.GetScanJob:
+		var args = struct {
+			Id string `json:"id"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.GetScanJob(ctx, args.Id))
+
+	case RPC.Service.GetScanJobs:
+		resp.Set(s.GetScanJobs(ctx))
+
+	case RPC.Service.CancelScan:
+		var args = struct {
+			Id string `json:"id"`
+		}{}
+
+		if zenrpc.IsArray(params) {
+			if params, err = zenrpc.ConvertToObject([]string{"id"}, params); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &args); err != nil {
+				return zenrpc.NewResponseError(nil, zenrpc.InvalidParams, "", err.Error())
+			}
+		}
+
+		resp.Set(s.CancelScan(ctx, args.Id))
 
 	default:
 		resp = zenrpc.NewResponseError(nil, zenrpc.MethodNotFound, "", nil)
@@ -0,0 +1,173 @@
+package vfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	posterKind       = "poster"
+	maxPosterSeekSec = 1.0
+
+	videoStream = "v:0"
+	audioStream = "a:0"
+)
+
+var errNoMediaStream = errors.New("no media stream")
+
+// MediaInfo is a video or audio stream info returned by ffprobe.
+type MediaInfo struct {
+	Duration float64
+	Width    int
+	Height   int
+	Codec    string
+}
+
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecName string `json:"codec_name"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+// videoIndexer gets video duration, resolution and codec via ffprobe, extracts poster frame and calculates its blurhash.
+type videoIndexer struct {
+	ffprobePath  string
+	ffmpegPath   string
+	calcBlurHash bool
+}
+
+func (vi videoIndexer) Index(ctx context.Context, f IndexedFile, info *HashInfo) error {
+	mi, err := probeMedia(ctx, vi.ffprobePath, f.Path, videoStream)
+	if err != nil {
+		return err
+	}
+
+	info.Width = mi.Width
+	info.Height = mi.Height
+	info.Params.Duration = mi.Duration
+	info.Params.Codec = mi.Codec
+
+	// generate poster frame
+	if vi.ffmpegPath == "" {
+		return nil
+	}
+
+	poster, posterPath := f.DerivedFile(posterKind)
+	if err = extractPoster(ctx, vi.ffmpegPath, f.Path, posterPath, mi.Duration); err != nil {
+		return err
+	}
+	info.Params.Poster = poster
+
+	// calculate poster blurhash
+	if !vi.calcBlurHash {
+		return nil
+	}
+
+	img, err := decodeImage(posterPath)
+	if err != nil {
+		return err
+	}
+
+	info.BlurHash, err = blurHash(img, vi.calcBlurHash)
+	return err
+}
+
+// probeMedia runs ffprobe and returns info for selected stream, e.g. "v:0" for first video stream.
+func probeMedia(ctx context.Context, ffprobePath, filename, stream string) (MediaInfo, error) {
+	//nolint:gosec
+	out, err := exec.CommandContext(ctx, ffprobePath,
+		"-v", "error",
+		"-select_streams", stream,
+		"-show_entries", "stream=codec_name,width,height:format=duration",
+		"-of", "json",
+		filename,
+	).Output()
+	if err != nil {
+		return MediaInfo{}, fmt.Errorf("ffprobe failed: %w", execError(err))
+	}
+
+	return parseFFprobe(out)
+}
+
+// parseFFprobe parses ffprobe json output.
+func parseFFprobe(data []byte) (MediaInfo, error) {
+	var out ffprobeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return MediaInfo{}, err
+	}
+
+	if len(out.Streams) == 0 {
+		return MediaInfo{}, errNoMediaStream
+	}
+
+	mi := MediaInfo{
+		Width:  out.Streams[0].Width,
+		Height: out.Streams[0].Height,
+		Codec:  out.Streams[0].CodecName,
+	}
+
+	if out.Format.Duration != "" {
+		d, err := strconv.ParseFloat(out.Format.Duration, 64)
+		if err != nil {
+			return mi, fmt.Errorf("invalid duration: %w", err)
+		}
+		mi.Duration = d
+	}
+
+	return mi, nil
+}
+
+// extractPoster saves a single video frame as jpeg into dst file.
+func extractPoster(ctx context.Context, ffmpegPath, filename, dst string, duration float64) error {
+	// take frame from the first second, or from the middle for short videos
+	at := min(duration/2, maxPosterSeekSec)
+
+	tmp := dst + ".tmp.jpg"
+	//nolint:gosec
+	_, err := exec.CommandContext(ctx, ffmpegPath,
+		"-v", "error",
+		"-y",
+		"-ss", strconv.FormatFloat(at, 'f', 3, 64),
+		"-i", filename,
+		"-frames:v", "1",
+		"-q:v", "3",
+		tmp,
+	).Output()
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("ffmpeg failed: %w", execError(err))
+	}
+
+	if err = os.Chmod(tmp, defaultHashFileModePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, dst)
+}
+
+// derivedFile returns relative path for a file generated from hash file, e.g. poster or thumbnail.
+// e.g. "7/0c/70c565ef460af43688b7ee6251028db9.poster.jpg"
+func derivedFile(relFilepath, kind string) string {
+	return strings.TrimSuffix(relFilepath, filepath.Ext(relFilepath)) + "." + kind + ".jpg"
+}
+
+// execError adds stderr output to exec error.
+func execError(err error) error {
+	var ee *exec.ExitError
+	if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return err
+}
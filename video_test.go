@@ -0,0 +1,59 @@
+package vfs
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_parseFFprobe(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    MediaInfo
+		wantErr error
+	}{
+		{
+			name: "h264 video",
+			data: `{"programs":[],"streams":[{"codec_name":"h264","width":1920,"height":1080}],"format":{"duration":"12.480000"}}`,
+			want: MediaInfo{Duration: 12.48, Width: 1920, Height: 1080, Codec: "h264"},
+		},
+		{
+			name: "without duration",
+			data: `{"streams":[{"codec_name":"vp9","width":640,"height":360}],"format":{}}`,
+			want: MediaInfo{Width: 640, Height: 360, Codec: "vp9"},
+		},
+		{
+			name: "audio",
+			data: `{"streams":[{"codec_name":"mp3"}],"format":{"duration":"185.3"}}`,
+			want: MediaInfo{Duration: 185.3, Codec: "mp3"},
+		},
+		{
+			name:    "no media stream",
+			data:    `{"streams":[],"format":{"duration":"1.0"}}`,
+			wantErr: errNoMediaStream,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFFprobe([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseFFprobe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseFFprobe() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_derivedFile(t *testing.T) {
+	got := derivedFile("test/7/0c/70c565ef460af43688b7ee6251028db9.mp4", posterKind)
+	if got != "test/7/0c/70c565ef460af43688b7ee6251028db9.poster.jpg" {
+		t.Fatal(got)
+	}
+
+	// derived files must be ignored by scanner
+	if isHashFile("test", got) {
+		t.Fatalf("isHashFile(%s) = true", got)
+	}
+}
@@ -0,0 +1,316 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/vmkteam/embedlog"
+)
+
+const (
+	defaultWatchDebounce     = 2 * time.Second
+	defaultWatchScanInterval = time.Hour
+)
+
+// errWatchLimit is returned if fsnotify watch limit is exceeded, see fs.inotify.max_user_watches.
+var errWatchLimit = errors.New("watch limit exceeded")
+
+// watchedFile is a created hash file waiting for debounce.
+type watchedFile struct {
+	ns      string
+	relPath string
+	at      time.Time
+}
+
+// HashWatcher watches namespaces folders via fsnotify and adds hash files written outside vfs into vfsHashes.
+// Files are added after debounce interval since last write. If watch limit is exceeded,
+// watcher falls back to periodic incremental scans.
+type HashWatcher struct {
+	embedlog.Logger
+	hi           *HashIndexer
+	repo         *db.VfsRepo
+	debounce     time.Duration
+	scanInterval time.Duration
+
+	mu      sync.Mutex
+	pending map[string]watchedFile
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHashWatcher returns new watcher. Default values are used for zero debounce and scanInterval.
+func NewHashWatcher(sl embedlog.Logger, hi *HashIndexer, repo *db.VfsRepo, debounce, scanInterval time.Duration) *HashWatcher {
+	if debounce <= 0 {
+		debounce = defaultWatchDebounce
+	}
+	if scanInterval <= 0 {
+		scanInterval = defaultWatchScanInterval
+	}
+
+	return &HashWatcher{
+		Logger:       sl,
+		hi:           hi,
+		repo:         repo,
+		debounce:     debounce,
+		scanInterval: scanInterval,
+		pending:      make(map[string]watchedFile),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start watches files until Stop is called.
+func (hw *HashWatcher) Start() {
+	ctx := context.Background()
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		hw.Error(ctx, "fsnotify init failed, using periodic scans", "err", err)
+		hw.runScans(ctx)
+		return
+	}
+	defer func(w *fsnotify.Watcher) {
+		_ = w.Close()
+	}(w)
+
+	if err = hw.addTree(w, hw.hi.vfs.cfg.Path, false); errors.Is(err, errWatchLimit) {
+		hw.Error(ctx, "fsnotify watch failed, using periodic scans", "err", err)
+		_ = w.Close()
+		hw.runScans(ctx)
+		return
+	} else if err != nil {
+		hw.Error(ctx, "fsnotify watch failed", "err", err)
+	}
+	hw.Print(ctx, "watching files", "path", hw.hi.vfs.cfg.Path, "watches", len(w.WatchList()))
+
+	t := time.NewTicker(hw.debounce / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-hw.stop:
+			return
+		case e, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if err := hw.handleEvent(w, e); errors.Is(err, errWatchLimit) {
+				hw.Error(ctx, "fsnotify watch failed, using periodic scans", "err", err, "path", e.Name)
+				_ = w.Close()
+				// add pending files without debounce, periodic scans could start much later
+				hw.flush(ctx, time.Now().Add(hw.debounce))
+				hw.runScans(ctx)
+				return
+			} else if err != nil {
+				hw.Error(ctx, "fsnotify watch failed", "err", err, "path", e.Name)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			hw.Error(ctx, "fsnotify error", "err", err)
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				// some events are lost
+				hw.startScan(ctx)
+			}
+		case <-t.C:
+			hw.flush(ctx, time.Now())
+		}
+	}
+}
+
+// Stop stops watcher.
+func (hw *HashWatcher) Stop() {
+	hw.stopOnce.Do(func() {
+		close(hw.stop)
+	})
+}
+
+// handleEvent watches created directories and adds created or written hash files to pending list.
+func (hw *HashWatcher) handleEvent(w *fsnotify.Watcher, e fsnotify.Event) error {
+	if !e.Has(fsnotify.Create) && !e.Has(fsnotify.Write) {
+		return nil
+	}
+
+	fi, err := os.Stat(e.Name)
+	if err != nil {
+		// file is already removed or renamed
+		return nil
+	}
+
+	if !fi.IsDir() {
+		hw.add(e.Name, time.Now())
+		return nil
+	}
+
+	// new directory: files could be written before watch is added
+	if e.Has(fsnotify.Create) {
+		return hw.addTree(w, e.Name, true)
+	}
+
+	return nil
+}
+
+// addTree adds watches for namespaces and hash directories under root. Existing files are added to pending list if addFiles is set.
+// Directories removed during walk (e.g. rsync temp dirs) are skipped. Returns errWatchLimit if watch limit is exceeded.
+func (hw *HashWatcher) addTree(w *fsnotify.Watcher, root string, addFiles bool) error {
+	now := time.Now()
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			if addFiles {
+				hw.add(path, now)
+			}
+			return nil
+		}
+
+		if !hw.isWatchedDir(path) {
+			return filepath.SkipDir
+		}
+
+		if err = w.Add(path); errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE) {
+			return fmt.Errorf("%w: %w", errWatchLimit, err)
+		} else if errors.Is(err, fs.ErrNotExist) {
+			return filepath.SkipDir
+		} else if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// isWatchedDir checks that directory is vfs root, namespace or hash directory.
+func (hw *HashWatcher) isWatchedDir(path string) bool {
+	relPath, err := filepath.Rel(hw.hi.vfs.cfg.Path, path)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return false
+	} else if relPath == "." {
+		return true
+	}
+
+	ns := getNs(hw.hi.vfs.cfg.Namespaces, relPath)
+	if ns != "" {
+		relPath = strings.TrimPrefix(strings.TrimPrefix(relPath, ns), string(filepath.Separator))
+	}
+
+	return relPath == "" || (len(relPath) == 1 && isHex(rune(relPath[0]))) || isHashDir(relPath)
+}
+
+// add adds hash file to pending list or updates its last event time.
+func (hw *HashWatcher) add(path string, at time.Time) {
+	relPath, err := filepath.Rel(hw.hi.vfs.cfg.Path, path)
+	if err != nil {
+		return
+	}
+
+	ns := getNs(hw.hi.vfs.cfg.Namespaces, relPath)
+	if !isHashFile(ns, relPath) {
+		return
+	}
+
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+	hw.pending[relPath] = watchedFile{ns: ns, relPath: relPath, at: at}
+}
+
+// ready returns and removes pending files without events for debounce interval.
+func (hw *HashWatcher) ready(now time.Time) []watchedFile {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	var list []watchedFile
+	for k, f := range hw.pending {
+		if now.Sub(f.at) >= hw.debounce {
+			list = append(list, f)
+			delete(hw.pending, k)
+		}
+	}
+
+	return list
+}
+
+// requeue returns files into pending list, files with newer events are kept as is.
+func (hw *HashWatcher) requeue(files []watchedFile) {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	for _, f := range files {
+		if _, ok := hw.pending[f.relPath]; !ok {
+			hw.pending[f.relPath] = f
+		}
+	}
+}
+
+// flush adds ready files into vfsHashes. Files are returned into pending list if adding is failed.
+func (hw *HashWatcher) flush(ctx context.Context, now time.Time) {
+	files := hw.ready(now)
+	if len(files) == 0 {
+		return
+	}
+
+	hashes := make([]db.VfsHash, 0, len(files))
+	added := make([]watchedFile, 0, len(files))
+	for _, f := range files {
+		fi, err := os.Stat(hw.hi.vfs.Path("", f.relPath))
+		if err != nil {
+			continue
+		}
+
+		ext := filepath.Ext(f.relPath)
+		hashes = append(hashes, db.VfsHash{
+			Hash:      strings.TrimSuffix(filepath.Base(f.relPath), ext),
+			Namespace: hashNamespace(f.ns),
+			Extension: strings.TrimPrefix(ext, "."),
+			FileSize:  int(fi.Size()),
+			CreatedAt: now,
+		})
+		added = append(added, f)
+	}
+
+	n, err := hw.repo.AddVfsHashes(ctx, hashes)
+	if err != nil {
+		hw.requeue(added)
+	}
+	hw.PrintOrErr(ctx, "watched files added", err, "files", len(hashes), "added", n)
+}
+
+// runScans runs incremental scans with scanInterval until Stop is called.
+func (hw *HashWatcher) runScans(ctx context.Context) {
+	t := time.NewTicker(hw.scanInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-hw.stop:
+			return
+		case <-t.C:
+			hw.startScan(ctx)
+		}
+	}
+}
+
+// startScan starts incremental scan of all namespaces.
+func (hw *HashWatcher) startScan(ctx context.Context) {
+	job, err := hw.hi.StartScan(ScanOptions{Incremental: true})
+	if errors.Is(err, ErrScanRunning) {
+		return
+	}
+	hw.PrintOrErr(ctx, "watcher scan started", err, "job", job.ID)
+}
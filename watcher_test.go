@@ -0,0 +1,131 @@
+package vfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vmkteam/vfs/db"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/vmkteam/embedlog"
+)
+
+func newTestWatcher(t *testing.T) *HashWatcher {
+	t.Helper()
+	v, err := New(Config{Path: t.TempDir(), Namespaces: []string{"test"}}, embedlog.Logger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hi := NewHashIndexer(embedlog.Logger{}, db.DB{}, nil, v, 1, 1, false, nil)
+	return NewHashWatcher(embedlog.Logger{}, hi, nil, time.Second, 0)
+}
+
+func TestHashWatcher_isWatchedDir(t *testing.T) {
+	hw := newTestWatcher(t)
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "", want: true},
+		{path: "7", want: true},
+		{path: "7/0c", want: true},
+		{path: "test", want: true},
+		{path: "test/7/0c", want: true},
+		{path: "test2", want: false},
+		{path: "small", want: false},
+		{path: "7/0c/tmp", want: false},
+		{path: "../other", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := hw.isWatchedDir(filepath.Join(hw.hi.vfs.cfg.Path, tt.path)); got != tt.want {
+				t.Errorf("isWatchedDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashWatcher_addTree(t *testing.T) {
+	hw := newTestWatcher(t)
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	files := []string{
+		"test/7/0c/70c565ef460af43688b7ee6251028db9.jpg",
+		"test/7/0c/70c565ef460af43688b7ee6251028db9.poster.jpg",
+		"8/0c/80c565ef460af43688b7ee6251028db9.png",
+		"small/8/0c/80c565ef460af43688b7ee6251028db9.png",
+	}
+	for _, f := range files {
+		path := filepath.Join(hw.hi.vfs.cfg.Path, f)
+		if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(path, []byte(f), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	if err = hw.addTree(w, hw.hi.vfs.cfg.Path, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// root, test, test/7, test/7/0c, 8, 8/0c
+	if got := len(w.WatchList()); got != 6 {
+		t.Errorf("addTree() watches = %v, want 6", got)
+	}
+
+	// debounce
+	if got := hw.ready(now); len(got) != 0 {
+		t.Errorf("ready() = %v, want empty", got)
+	}
+
+	got := hw.ready(now.Add(time.Minute))
+	if len(got) != 2 {
+		t.Fatalf("ready() = %v, want 2 files", got)
+	}
+	for _, f := range got {
+		if f.relPath != filepath.FromSlash(files[0]) && f.relPath != filepath.FromSlash(files[2]) {
+			t.Errorf("ready() unexpected file %v", f)
+		}
+		if f.relPath == filepath.FromSlash(files[0]) && f.ns != "test" {
+			t.Errorf("ready() ns = %v, want test", f.ns)
+		}
+	}
+
+	if got := hw.ready(now.Add(time.Minute)); len(got) != 0 {
+		t.Errorf("ready() = %v, want empty after flush", got)
+	}
+	// removed directory, e.g. rsync temp dir
+	if err = hw.addTree(w, filepath.Join(hw.hi.vfs.cfg.Path, "9"), true); err != nil {
+		t.Errorf("addTree() removed dir err = %v, want nil", err)
+	}
+}
+
+func TestHashWatcher_requeue(t *testing.T) {
+	hw := newTestWatcher(t)
+	now := time.Now()
+	first, second := filepath.Join("7", "0c", "70c565ef460af43688b7ee6251028db9.jpg"), filepath.Join("test", "6", "4a", "64a9f060983200709061894cc5f69f83.jpg")
+	hw.add(filepath.Join(hw.hi.vfs.cfg.Path, first), now)
+	hw.add(filepath.Join(hw.hi.vfs.cfg.Path, second), now)
+
+	files := hw.ready(now.Add(2 * time.Second))
+	if len(files) != 2 || len(hw.pending) != 0 {
+		t.Fatalf("ready() = %v, pending = %v", files, hw.pending)
+	}
+
+	// failed files are returned, newer events are kept
+	newer := now.Add(3 * time.Second)
+	hw.add(filepath.Join(hw.hi.vfs.cfg.Path, second), newer)
+	hw.requeue(files)
+	if len(hw.pending) != 2 || !hw.pending[first].at.Equal(now) || !hw.pending[second].at.Equal(newer) {
+		t.Errorf("requeue() pending = %v", hw.pending)
+	}
+}